@@ -0,0 +1,230 @@
+package api
+
+import (
+	"encoding/json"
+	"errors"
+	"net"
+	"net/http"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/blackprism/goti-snake/game"
+)
+
+const commandTimeout = time.Second
+
+var (
+	errNotRunning = errors.New("no game is running")
+	errTimeout    = errors.New("game loop is not responding")
+)
+
+func Listen(address string) (*Server, error) {
+	listener, err := net.Listen("tcp", address)
+	if err != nil {
+		return nil, err
+	}
+
+	server := &Server{
+		listener: listener,
+		commands: make(chan *command, 16),
+	}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/state", server.handleState)
+	mux.HandleFunc("/direction", server.handleDirection)
+	mux.HandleFunc("/pause", server.handleRun(pause))
+	mux.HandleFunc("/resume", server.handleRun(resume))
+	mux.HandleFunc("/new-game", server.handleRun(newGame))
+
+	server.http = &http.Server{Handler: mux}
+	go server.http.Serve(listener)
+
+	return server, nil
+}
+
+// Server exposes the board over HTTP. Handlers never touch the board
+// themselves: they queue commands that the game loop runs through Apply, so
+// the board is only ever used from the goroutine owning the window.
+type Server struct {
+	listener net.Listener
+	http     *http.Server
+	commands chan *command
+	mutex    sync.Mutex
+	state    game.State
+}
+
+// command is claimed once, either by the game loop which runs it or by the
+// handler giving up on it, so a command reported as failed never runs.
+type command struct {
+	run     func(board *game.Board) error
+	done    chan error
+	claimed int32
+}
+
+func (command *command) claim() bool {
+	return atomic.CompareAndSwapInt32(&command.claimed, 0, 1)
+}
+
+type directionRequest struct {
+	Direction game.Direction `json:"direction"`
+}
+
+type errorResponse struct {
+	Error string `json:"error"`
+}
+
+func (server *Server) Addr() net.Addr {
+	return server.listener.Addr()
+}
+
+func (server *Server) Publish(state game.State) {
+	server.mutex.Lock()
+	server.state = state
+	server.mutex.Unlock()
+}
+
+func (server *Server) Apply(board *game.Board) {
+	for {
+		select {
+		case command := <-server.commands:
+			if !command.claim() {
+				continue
+			}
+
+			err := command.run(board)
+			server.Publish(board.State())
+			command.done <- err
+		default:
+			return
+		}
+	}
+}
+
+func (server *Server) Close() error {
+	return server.http.Close()
+}
+
+func (server *Server) handleState(writer http.ResponseWriter, request *http.Request) {
+	if request.Method != http.MethodGet {
+		writeError(writer, http.StatusMethodNotAllowed, errors.New("method not allowed"))
+		return
+	}
+
+	server.writeState(writer)
+}
+
+func (server *Server) handleDirection(writer http.ResponseWriter, request *http.Request) {
+	if request.Method != http.MethodPost {
+		writeError(writer, http.StatusMethodNotAllowed, errors.New("method not allowed"))
+		return
+	}
+
+	var body directionRequest
+	if err := json.NewDecoder(request.Body).Decode(&body); err != nil {
+		writeError(writer, http.StatusBadRequest, err)
+		return
+	}
+
+	server.run(writer, func(board *game.Board) error {
+		if !board.Running() {
+			return errNotRunning
+		}
+
+		board.Turn(body.Direction)
+
+		return nil
+	})
+}
+
+func (server *Server) handleRun(run func(board *game.Board) error) http.HandlerFunc {
+	return func(writer http.ResponseWriter, request *http.Request) {
+		if request.Method != http.MethodPost {
+			writeError(writer, http.StatusMethodNotAllowed, errors.New("method not allowed"))
+			return
+		}
+
+		server.run(writer, run)
+	}
+}
+
+func (server *Server) run(writer http.ResponseWriter, run func(board *game.Board) error) {
+	command := &command{
+		run:  run,
+		done: make(chan error, 1),
+	}
+
+	timeout := time.NewTimer(commandTimeout)
+	defer timeout.Stop()
+
+	select {
+	case server.commands <- command:
+	case <-timeout.C:
+		writeError(writer, http.StatusServiceUnavailable, errTimeout)
+		return
+	}
+
+	var err error
+	select {
+	case err = <-command.done:
+	case <-timeout.C:
+		if command.claim() {
+			writeError(writer, http.StatusServiceUnavailable, errTimeout)
+			return
+		}
+
+		// The game loop took the command just in time, its result follows.
+		err = <-command.done
+	}
+
+	if err != nil {
+		writeError(writer, http.StatusConflict, err)
+		return
+	}
+
+	server.writeState(writer)
+}
+
+func (server *Server) writeState(writer http.ResponseWriter) {
+	server.mutex.Lock()
+	state := server.state
+	server.mutex.Unlock()
+
+	writeJSON(writer, http.StatusOK, state)
+}
+
+func pause(board *game.Board) error {
+	if board.Status() != game.Continue {
+		return errNotRunning
+	}
+
+	board.SetPaused(true)
+
+	return nil
+}
+
+func resume(board *game.Board) error {
+	if board.Status() != game.Pause {
+		return errors.New("game is not paused")
+	}
+
+	board.SetPaused(false)
+
+	return nil
+}
+
+func newGame(board *game.Board) error {
+	board.Start()
+
+	return nil
+}
+
+func writeError(writer http.ResponseWriter, status int, err error) {
+	writeJSON(writer, status, errorResponse{Error: err.Error()})
+}
+
+func writeJSON(writer http.ResponseWriter, status int, value interface{}) {
+	writer.Header().Set("Content-Type", "application/json")
+	writer.WriteHeader(status)
+	_ = json.NewEncoder(writer).Encode(value)
+}
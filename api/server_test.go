@@ -0,0 +1,73 @@
+package api
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/blackprism/goti-snake/game"
+)
+
+func newBoard() *game.Board {
+	position := game.NewCoordinateConverter(20, 60, 28)
+	snake := game.NewSnake(20, position)
+	snake.Init()
+
+	return game.NewBoard(snake, 600, 20, 20, 0.2, position)
+}
+
+func TestTimedOutCommandNeverRuns(t *testing.T) {
+	server, err := Listen("127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer server.Close()
+
+	board := newBoard()
+	board.Start()
+
+	response, err := http.Post("http://"+server.Addr().String()+"/pause", "application/json", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	response.Body.Close()
+
+	if response.StatusCode != http.StatusServiceUnavailable {
+		t.Fatalf("status = %d, want %d", response.StatusCode, http.StatusServiceUnavailable)
+	}
+
+	server.Apply(board)
+
+	if board.Status() != game.Continue {
+		t.Fatalf("board status = %s, the timed out pause ran", board.Status())
+	}
+}
+
+func TestAppliedCommandAnswers(t *testing.T) {
+	server, err := Listen("127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer server.Close()
+
+	board := newBoard()
+	board.Start()
+
+	result := make(chan int)
+	go func() {
+		response, err := http.Post("http://"+server.Addr().String()+"/pause", "application/json", nil)
+		if err != nil {
+			result <- 0
+			return
+		}
+		response.Body.Close()
+		result <- response.StatusCode
+	}()
+
+	for board.Status() != game.Pause {
+		server.Apply(board)
+	}
+
+	if status := <-result; status != http.StatusOK {
+		t.Fatalf("status = %d, want %d", status, http.StatusOK)
+	}
+}
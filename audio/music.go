@@ -0,0 +1,92 @@
+package audio
+
+import "math"
+
+// rest is a step of the melody without a note.
+const rest = -1
+
+// melody and bass are in semitones above A3, one step per entry, the loop
+// is 32 steps long.
+var (
+	melody = []int{
+		12, rest, 15, 17, 19, rest, 17, 15,
+		12, rest, 10, 12, 15, rest, rest, rest,
+		12, rest, 15, 17, 19, rest, 22, 19,
+		17, 15, 17, 19, 12, rest, rest, rest,
+	}
+	bass = []int{
+		0, rest, 0, rest, 0, rest, 0, rest,
+		-4, rest, -4, rest, -2, rest, -2, rest,
+		0, rest, 0, rest, 3, rest, 3, rest,
+		-2, rest, -2, rest, 0, rest, 0, rest,
+	}
+)
+
+const (
+	// Music steps twice per move of the snake, within these bounds.
+	stepsPerMove   = 2
+	minStepSeconds = 0.06
+	maxStepSeconds = 0.3
+	musicVolume    = 0.12
+)
+
+// Music is a chiptune loop rendered on demand, its tempo follows the speed
+// of the snake.
+type Music struct {
+	stepSeconds float64
+	step        int
+	elapsed     int
+	lead        oscillator
+	low         oscillator
+}
+
+func NewMusic() *Music {
+	return &Music{
+		stepSeconds: maxStepSeconds,
+		lead:        oscillator{wave: Square, dutyCycle: 0.25},
+		low:         oscillator{wave: Triangle},
+	}
+}
+
+// SetSpeed sets the tempo from the speed of the snake, in cells per
+// second.
+func (music *Music) SetSpeed(cellsPerSecond float32) {
+	if cellsPerSecond <= 0 {
+		music.stepSeconds = maxStepSeconds
+		return
+	}
+
+	seconds := 1 / (float64(cellsPerSecond) * stepsPerMove)
+	music.stepSeconds = math.Max(minStepSeconds, math.Min(maxStepSeconds, seconds))
+}
+
+// Read renders the next samples of the loop into samples.
+func (music *Music) Read(samples []float32) {
+	for index := range samples {
+		length := int(music.stepSeconds * SampleRate)
+		if music.elapsed >= length {
+			music.elapsed = 0
+			music.step = (music.step + 1) % len(melody)
+		}
+
+		// Each note fades out along its step, which is all the envelope a
+		// loop this fast needs.
+		fade := 1 - float64(music.elapsed)/float64(length)
+
+		var sample float64
+		if note := melody[music.step]; note != rest {
+			sample += music.lead.next(frequency(note)) * fade
+		}
+		if note := bass[music.step]; note != rest {
+			sample += music.low.next(frequency(note)) * fade
+		}
+
+		samples[index] = float32(sample * musicVolume)
+		music.elapsed++
+	}
+}
+
+// frequency of the note, in semitones above A3.
+func frequency(semitones int) float64 {
+	return 220 * math.Pow(2, float64(semitones)/12)
+}
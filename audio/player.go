@@ -0,0 +1,162 @@
+package audio
+
+import rl "github.com/gen2brain/raylib-go/raylib"
+
+const (
+	// bufferFrames is the size raylib gives the buffers of a stream, a
+	// shorter update would be padded with silence.
+	bufferFrames = 4096
+	maxVoices    = 8
+)
+
+type voice struct {
+	samples  []float32
+	position int
+}
+
+// Player mixes the sounds being played and the music into one raylib audio
+// stream. A nil player or one without an audio device stays silent.
+type Player struct {
+	stream  rl.AudioStream
+	open    bool
+	buffer  []float32
+	voices  []voice
+	sounds  map[Sound][]float32
+	music   *Music
+	playing bool
+	volume  float32
+}
+
+// NewPlayer gives a player mixing in memory only, Open plays for real.
+func NewPlayer() *Player {
+	player := &Player{
+		buffer: make([]float32, bufferFrames),
+		sounds: map[Sound][]float32{},
+		music:  NewMusic(),
+		volume: 1,
+	}
+
+	for _, sound := range Sounds() {
+		player.sounds[sound] = Synthesize(sound)
+	}
+
+	return player
+}
+
+// Open starts the audio device and the stream, the player stays silent
+// when there is no device.
+func Open() *Player {
+	player := NewPlayer()
+
+	rl.InitAudioDevice()
+	if !rl.IsAudioDeviceReady() {
+		return player
+	}
+
+	player.stream = rl.InitAudioStream(SampleRate, 32, 1)
+	rl.PlayAudioStream(player.stream)
+	player.open = true
+
+	return player
+}
+
+func (player *Player) Close() {
+	if player == nil || !player.open {
+		return
+	}
+
+	rl.CloseAudioStream(player.stream)
+	rl.CloseAudioDevice()
+	player.open = false
+}
+
+// Play starts the sound over the ones already playing, the oldest one is
+// dropped when too many play at once.
+func (player *Player) Play(sound Sound) {
+	if player == nil {
+		return
+	}
+
+	if len(player.voices) == maxVoices {
+		player.voices = player.voices[1:]
+	}
+
+	player.voices = append(player.voices, voice{samples: player.sounds[sound]})
+}
+
+// SetVolume sets the volume, from 0 to 1, of the sounds and the music.
+func (player *Player) SetVolume(volume float32) {
+	if player == nil {
+		return
+	}
+
+	player.volume = volume
+}
+
+// SetMusic plays or pauses the music loop, it resumes where it stopped.
+func (player *Player) SetMusic(playing bool) {
+	if player == nil {
+		return
+	}
+
+	player.playing = playing
+}
+
+// SetSpeed makes the music follow the speed of the snake, in cells per
+// second.
+func (player *Player) SetSpeed(cellsPerSecond float32) {
+	if player == nil {
+		return
+	}
+
+	player.music.SetSpeed(cellsPerSecond)
+}
+
+// Update hands the stream the next samples once it has played the last
+// ones, it is called every frame.
+func (player *Player) Update() {
+	if player == nil || !player.open {
+		return
+	}
+
+	if rl.IsAudioStreamProcessed(player.stream) {
+		player.Mix(player.buffer)
+		rl.UpdateAudioStream(player.stream, player.buffer, int32(len(player.buffer)))
+	}
+}
+
+// Mix renders the next samples of the music and the sounds into samples,
+// the sounds which are over are forgotten.
+func (player *Player) Mix(samples []float32) {
+	if player.playing {
+		player.music.Read(samples)
+	} else {
+		for index := range samples {
+			samples[index] = 0
+		}
+	}
+
+	voices := player.voices[:0]
+	for _, voice := range player.voices {
+		left := voice.samples[voice.position:]
+		for index := 0; index < len(samples) && index < len(left); index++ {
+			samples[index] += left[index]
+		}
+
+		voice.position += len(samples)
+		if voice.position < len(voice.samples) {
+			voices = append(voices, voice)
+		}
+	}
+	player.voices = voices
+
+	for index, sample := range samples {
+		sample *= player.volume
+		if sample > 1 {
+			sample = 1
+		} else if sample < -1 {
+			sample = -1
+		}
+		samples[index] = sample
+	}
+}
@@ -0,0 +1,63 @@
+package audio
+
+import "testing"
+
+func TestMixSilence(t *testing.T) {
+	player := NewPlayer()
+	samples := []float32{1, 1, 1}
+
+	player.Mix(samples)
+
+	for _, sample := range samples {
+		if sample != 0 {
+			t.Fatalf("a player without sound nor music mixed %v", samples)
+		}
+	}
+}
+
+func TestMixForgetsFinishedSounds(t *testing.T) {
+	player := NewPlayer()
+	player.Play(Blip)
+
+	samples := make([]float32, len(player.sounds[Blip])+1)
+	player.Mix(samples)
+
+	if len(player.voices) != 0 {
+		t.Fatal("the blip should be over")
+	}
+
+	for index, sample := range player.sounds[Blip] {
+		if samples[index] != sample {
+			t.Fatalf("sample %d = %f, want %f", index, samples[index], sample)
+		}
+	}
+}
+
+func TestMixMusicUnderSounds(t *testing.T) {
+	player := NewPlayer()
+	player.SetMusic(true)
+	player.Play(Apple)
+
+	music := make([]float32, 512)
+	NewMusic().Read(music)
+
+	samples := make([]float32, 512)
+	player.Mix(samples)
+
+	for index := range samples {
+		if want := music[index] + player.sounds[Apple][index]; samples[index] != want {
+			t.Fatalf("sample %d = %f, want %f", index, samples[index], want)
+		}
+	}
+}
+
+func TestNilPlayer(t *testing.T) {
+	var player *Player
+
+	player.Play(Apple)
+	player.SetVolume(0.5)
+	player.SetMusic(true)
+	player.SetSpeed(10)
+	player.Update()
+	player.Close()
+}
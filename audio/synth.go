@@ -0,0 +1,161 @@
+package audio
+
+import "math"
+
+// SampleRate is the number of mono samples per second of every sound, the
+// samples are floats between -1 and 1.
+const SampleRate = 44100
+
+type Wave int
+
+const (
+	Square   Wave = 0
+	Triangle Wave = 1
+	Sawtooth Wave = 2
+	Noise    Wave = 3
+)
+
+// Tone is one note sliding in pitch from From to To hertz, with a short
+// attack and a linear release so that it does not click.
+type Tone struct {
+	Wave      Wave
+	From      float64
+	To        float64
+	Seconds   float64
+	Volume    float64
+	Attack    float64
+	Release   float64
+	DutyCycle float64
+}
+
+type Sound int
+
+const (
+	Apple   Sound = 0
+	Turn    Sound = 1
+	Death   Sound = 2
+	Victory Sound = 3
+	Blip    Sound = 4
+)
+
+var sounds = map[Sound][]Tone{
+	Apple: {
+		{Wave: Square, From: 660, To: 990, Seconds: 0.06, Volume: 0.35, Attack: 0.005, Release: 0.03},
+		{Wave: Square, From: 990, To: 1320, Seconds: 0.08, Volume: 0.35, Attack: 0.005, Release: 0.06},
+	},
+	Turn: {
+		{Wave: Triangle, From: 330, To: 300, Seconds: 0.03, Volume: 0.3, Attack: 0.002, Release: 0.02},
+	},
+	Death: {
+		{Wave: Sawtooth, From: 440, To: 55, Seconds: 0.5, Volume: 0.4, Attack: 0.005, Release: 0.3},
+		{Wave: Noise, From: 0, To: 0, Seconds: 0.25, Volume: 0.25, Attack: 0.001, Release: 0.24},
+	},
+	Victory: {
+		{Wave: Square, From: 523.25, To: 523.25, Seconds: 0.12, Volume: 0.3, Attack: 0.005, Release: 0.04, DutyCycle: 0.25},
+		{Wave: Square, From: 659.25, To: 659.25, Seconds: 0.12, Volume: 0.3, Attack: 0.005, Release: 0.04, DutyCycle: 0.25},
+		{Wave: Square, From: 783.99, To: 783.99, Seconds: 0.12, Volume: 0.3, Attack: 0.005, Release: 0.04, DutyCycle: 0.25},
+		{Wave: Square, From: 1046.5, To: 1046.5, Seconds: 0.4, Volume: 0.3, Attack: 0.005, Release: 0.3, DutyCycle: 0.25},
+	},
+	Blip: {
+		{Wave: Square, From: 880, To: 880, Seconds: 0.04, Volume: 0.2, Attack: 0.002, Release: 0.03},
+	},
+}
+
+var soundNames = map[Sound]string{
+	Apple:   "apple",
+	Turn:    "turn",
+	Death:   "death",
+	Victory: "victory",
+	Blip:    "blip",
+}
+
+func (sound Sound) String() string {
+	return soundNames[sound]
+}
+
+// Sounds lists every sound, in order.
+func Sounds() []Sound {
+	return []Sound{Apple, Turn, Death, Victory, Blip}
+}
+
+// Synthesize renders the tones of the sound one after the other.
+func Synthesize(sound Sound) []float32 {
+	var samples []float32
+	for _, tone := range sounds[sound] {
+		samples = append(samples, tone.Render()...)
+	}
+
+	return samples
+}
+
+// Render computes the samples of the tone, the noise is seeded the same
+// way every time so that a sound always gives the same samples.
+func (tone Tone) Render() []float32 {
+	count := int(tone.Seconds * SampleRate)
+	samples := make([]float32, count)
+	oscillator := oscillator{wave: tone.Wave, dutyCycle: tone.DutyCycle, noise: 1}
+
+	for index := range samples {
+		progress := float64(index) / float64(count)
+		frequency := tone.From + (tone.To-tone.From)*progress
+		samples[index] = float32(oscillator.next(frequency) * tone.Volume * tone.envelope(float64(index)/SampleRate))
+	}
+
+	return samples
+}
+
+// envelope is the volume ratio at the time, in seconds, of the tone.
+func (tone Tone) envelope(time float64) float64 {
+	if tone.Attack > 0 && time < tone.Attack {
+		return time / tone.Attack
+	}
+
+	left := tone.Seconds - time
+	if tone.Release > 0 && left < tone.Release {
+		return math.Max(0, left/tone.Release)
+	}
+
+	return 1
+}
+
+// oscillator keeps the phase between samples so that the pitch can change
+// without a jump in the wave.
+type oscillator struct {
+	wave      Wave
+	dutyCycle float64
+	phase     float64
+	noise     uint16
+	level     float64
+}
+
+func (oscillator *oscillator) next(frequency float64) float64 {
+	previous := oscillator.phase
+	oscillator.phase = math.Mod(oscillator.phase+frequency/SampleRate, 1)
+
+	switch oscillator.wave {
+	case Square:
+		duty := oscillator.dutyCycle
+		if duty == 0 {
+			duty = 0.5
+		}
+		if oscillator.phase < duty {
+			return 1
+		}
+		return -1
+	case Triangle:
+		return 4*math.Abs(oscillator.phase-0.5) - 1
+	case Sawtooth:
+		return 2*oscillator.phase - 1
+	case Noise:
+		// The noise of the old sound chips, a linear feedback shift
+		// register stepped at every sample or every period when pitched.
+		if frequency == 0 || oscillator.phase < previous {
+			bit := (oscillator.noise ^ oscillator.noise>>1) & 1
+			oscillator.noise = oscillator.noise>>1 | bit<<14
+			oscillator.level = float64(oscillator.noise&1)*2 - 1
+		}
+		return oscillator.level
+	}
+
+	return 0
+}
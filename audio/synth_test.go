@@ -0,0 +1,61 @@
+package audio
+
+import (
+	"math"
+	"reflect"
+	"testing"
+)
+
+func TestRenderLength(t *testing.T) {
+	tone := Tone{Wave: Square, From: 440, To: 440, Seconds: 0.1, Volume: 0.5}
+
+	if samples := tone.Render(); len(samples) != SampleRate/10 {
+		t.Fatalf("%d samples, want %d", len(samples), SampleRate/10)
+	}
+}
+
+func TestRenderVolume(t *testing.T) {
+	for _, wave := range []Wave{Square, Triangle, Sawtooth, Noise} {
+		tone := Tone{Wave: wave, From: 440, To: 880, Seconds: 0.1, Volume: 0.5, Attack: 0.01, Release: 0.02}
+		samples := tone.Render()
+
+		peak := 0.0
+		for _, sample := range samples {
+			peak = math.Max(peak, math.Abs(float64(sample)))
+		}
+
+		if peak > 0.5 || peak < 0.4 {
+			t.Errorf("wave %d peaks at %f, want up to 0.5", wave, peak)
+		}
+
+		if samples[0] != 0 || math.Abs(float64(samples[len(samples)-1])) > 0.01 {
+			t.Errorf("wave %d clicks: starts at %f and ends at %f", wave, samples[0], samples[len(samples)-1])
+		}
+	}
+}
+
+func TestSynthesize(t *testing.T) {
+	for _, sound := range Sounds() {
+		samples := Synthesize(sound)
+
+		want := 0
+		for _, tone := range sounds[sound] {
+			want += int(tone.Seconds * SampleRate)
+		}
+
+		if len(samples) != want {
+			t.Errorf("%s has %d samples, want %d", sound, len(samples), want)
+		}
+
+		for _, sample := range samples {
+			if sample > 1 || sample < -1 {
+				t.Errorf("%s goes past full scale: %f", sound, sample)
+				break
+			}
+		}
+
+		if !reflect.DeepEqual(samples, Synthesize(sound)) {
+			t.Errorf("%s changes between two syntheses", sound)
+		}
+	}
+}
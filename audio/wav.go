@@ -0,0 +1,60 @@
+package audio
+
+import (
+	"bytes"
+	"encoding/binary"
+	"io"
+	"io/ioutil"
+	"math"
+)
+
+// WriteWAV encodes the samples as a mono 16 bits PCM WAV file, a sound can
+// be listened to or checked without an audio device.
+func WriteWAV(writer io.Writer, samples []float32) error {
+	const (
+		channels       = 1
+		bitsPerSample  = 16
+		bytesPerSample = bitsPerSample / 8
+	)
+
+	size := uint32(len(samples) * bytesPerSample)
+
+	header := []interface{}{
+		[4]byte{'R', 'I', 'F', 'F'},
+		36 + size,
+		[4]byte{'W', 'A', 'V', 'E'},
+		[4]byte{'f', 'm', 't', ' '},
+		uint32(16),
+		uint16(1),
+		uint16(channels),
+		uint32(SampleRate),
+		uint32(SampleRate * channels * bytesPerSample),
+		uint16(channels * bytesPerSample),
+		uint16(bitsPerSample),
+		[4]byte{'d', 'a', 't', 'a'},
+		size,
+	}
+
+	for _, field := range header {
+		if err := binary.Write(writer, binary.LittleEndian, field); err != nil {
+			return err
+		}
+	}
+
+	pcm := make([]int16, len(samples))
+	for index, sample := range samples {
+		pcm[index] = int16(math.Max(-1, math.Min(1, float64(sample))) * math.MaxInt16)
+	}
+
+	return binary.Write(writer, binary.LittleEndian, pcm)
+}
+
+// SaveWAV writes the samples to a WAV file at path.
+func SaveWAV(path string, samples []float32) error {
+	var buffer bytes.Buffer
+	if err := WriteWAV(&buffer, samples); err != nil {
+		return err
+	}
+
+	return ioutil.WriteFile(path, buffer.Bytes(), 0644)
+}
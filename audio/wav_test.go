@@ -0,0 +1,54 @@
+package audio
+
+import (
+	"bytes"
+	"encoding/binary"
+	"testing"
+)
+
+func TestWriteWAV(t *testing.T) {
+	var buffer bytes.Buffer
+	if err := WriteWAV(&buffer, []float32{0, 1, -1, 2}); err != nil {
+		t.Fatal(err)
+	}
+
+	data := buffer.Bytes()
+	if len(data) != 44+4*2 {
+		t.Fatalf("%d bytes, want %d", len(data), 44+4*2)
+	}
+
+	for offset, tag := range map[int]string{0: "RIFF", 8: "WAVE", 12: "fmt ", 36: "data"} {
+		if got := string(data[offset : offset+4]); got != tag {
+			t.Errorf("bytes %d are %q, want %q", offset, got, tag)
+		}
+	}
+
+	fields := []struct {
+		name   string
+		offset int
+		got    uint32
+		want   uint32
+	}{
+		{"riff size", 4, binary.LittleEndian.Uint32(data[4:]), 36 + 8},
+		{"format", 20, uint32(binary.LittleEndian.Uint16(data[20:])), 1},
+		{"channels", 22, uint32(binary.LittleEndian.Uint16(data[22:])), 1},
+		{"sample rate", 24, binary.LittleEndian.Uint32(data[24:]), SampleRate},
+		{"byte rate", 28, binary.LittleEndian.Uint32(data[28:]), SampleRate * 2},
+		{"bits", 34, uint32(binary.LittleEndian.Uint16(data[34:])), 16},
+		{"data size", 40, binary.LittleEndian.Uint32(data[40:]), 8},
+	}
+
+	for _, field := range fields {
+		if field.got != field.want {
+			t.Errorf("%s at %d = %d, want %d", field.name, field.offset, field.got, field.want)
+		}
+	}
+
+	// The last sample is clipped to full scale.
+	want := []int16{0, 32767, -32767, 32767}
+	for index, sample := range want {
+		if got := int16(binary.LittleEndian.Uint16(data[44+2*index:])); got != sample {
+			t.Errorf("sample %d = %d, want %d", index, got, sample)
+		}
+	}
+}
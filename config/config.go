@@ -0,0 +1,171 @@
+package config
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	"github.com/blackprism/goti-snake/controls"
+	"github.com/blackprism/goti-snake/game"
+)
+
+// Grids are the board sizes offered in the settings, they all divide the
+// playing area in whole cells.
+var Grids = []int32{10, 14, 20, 28, 35, 40}
+
+// Config holds the player settings saved between launches.
+type Config struct {
+	path        string
+	Grid        int32             `json:"grid"`
+	Difficulty  game.Difficulty   `json:"difficulty"`
+	Walls       game.WallMode     `json:"walls"`
+	TailCut     bool              `json:"tailCut"`
+	DropCutTail bool              `json:"dropCutTail"`
+	GrowAtTail  bool              `json:"growAtTail"`
+	Theme       string            `json:"theme"`
+	ShapeCues   bool              `json:"shapeCues"`
+	Sprites     bool              `json:"sprites"`
+	Smooth      bool              `json:"smooth"`
+	Effects     bool              `json:"effects"`
+	Volume      float32           `json:"volume"`
+	Music       bool              `json:"music"`
+	Steering    controls.Steering `json:"steering"`
+	Profile     string            `json:"profile"`
+	Keys        controls.Bindings `json:"keys"`
+}
+
+func Default() *Config {
+	return &Config{
+		Grid:       20,
+		Difficulty: game.Normal,
+		Walls:      game.SolidWalls,
+		Theme:      game.ClassicTheme.Name,
+		Effects:    true,
+		Volume:     0.8,
+		Profile:    controls.DefaultProfile,
+		Keys:       controls.Profiles[controls.DefaultProfile].Copy(),
+	}
+}
+
+func DefaultPath() (string, error) {
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return "", err
+	}
+
+	return filepath.Join(dir, "goti-snake", "config.json"), nil
+}
+
+// ThemesDir is where the player puts their own theme files.
+func ThemesDir() (string, error) {
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return "", err
+	}
+
+	return filepath.Join(dir, "goti-snake", "themes"), nil
+}
+
+// Load reads the config at path, a missing file gives the default config.
+func Load(path string) (*Config, error) {
+	config := Default()
+	config.path = path
+
+	data, err := ioutil.ReadFile(path)
+	if os.IsNotExist(err) {
+		return config, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	// Decoding into the default bindings would merge them with the saved
+	// ones, a config without keys falls back on its profile instead.
+	config.Keys = nil
+	if err := json.Unmarshal(data, config); err != nil {
+		return nil, err
+	}
+
+	if config.Keys == nil {
+		if config.Keys, err = controls.Profile(config.Profile); err != nil {
+			return nil, err
+		}
+	}
+
+	profile, ok := controls.Profiles[config.Profile]
+	if !ok {
+		profile = controls.Profiles[controls.DefaultProfile]
+	}
+	config.Keys.Complete(profile)
+
+	if config.Grid < 3 {
+		return nil, fmt.Errorf("grid should be at least 3, got %d", config.Grid)
+	}
+
+	if config.Volume < 0 || config.Volume > 1 {
+		return nil, fmt.Errorf("volume should be between 0 and 1, got %.2f", config.Volume)
+	}
+
+	return config, nil
+}
+
+func (config *Config) Save() error {
+	if config.path == "" {
+		return nil
+	}
+
+	data, err := json.MarshalIndent(config, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(filepath.Dir(config.path), 0755); err != nil {
+		return err
+	}
+
+	return ioutil.WriteFile(config.path, data, 0644)
+}
+
+// Apply hands the settings to the board, the grid size and the rules only
+// take effect on the next new game while the looks show right away.
+func (config *Config) Apply(board *game.Board) {
+	board.SetGrid(config.Grid)
+	board.SetDifficulty(config.Difficulty)
+	board.SetWallMode(config.Walls)
+	board.SetTailCut(config.TailCut, config.DropCutTail)
+	board.SetGrowAtTail(config.GrowAtTail)
+
+	theme, _ := game.FindTheme(config.Theme)
+	board.SetTheme(theme)
+	board.SetShapeCues(config.ShapeCues)
+	board.SetSprites(config.Sprites)
+	board.SetSmooth(config.Smooth)
+	board.SetJuice(config.Effects)
+}
+
+// NextTheme returns the name of the theme following the current one, step
+// being 1 or -1.
+func (config *Config) NextTheme(step int) string {
+	names := game.ThemeNames()
+	for index, name := range names {
+		if name == config.Theme {
+			return names[(index+step+len(names))%len(names)]
+		}
+	}
+
+	return names[0]
+}
+
+// NextGrid returns the offered grid following the current one, step being
+// 1 or -1.
+func (config *Config) NextGrid(step int) int32 {
+	for index, grid := range Grids {
+		if grid == config.Grid {
+			return Grids[(index+step+len(Grids))%len(Grids)]
+		}
+	}
+
+	return Grids[0]
+}
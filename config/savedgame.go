@@ -0,0 +1,61 @@
+package config
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	"github.com/blackprism/goti-snake/game"
+)
+
+// SavedGamePath is where the game in progress is kept when the player
+// quits, to be resumed on the next launch.
+func SavedGamePath() (string, error) {
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return "", err
+	}
+
+	return filepath.Join(dir, "goti-snake", "game.json"), nil
+}
+
+func SaveGame(path string, state game.State) error {
+	data, err := json.MarshalIndent(state, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+
+	return ioutil.WriteFile(path, data, 0644)
+}
+
+// LoadGame reads the game saved at path, ok is false when there is none.
+// The file is left in place, RemoveGame drops it once the game is resumed.
+func LoadGame(path string) (state game.State, ok bool, err error) {
+	data, err := ioutil.ReadFile(path)
+	if os.IsNotExist(err) {
+		return state, false, nil
+	}
+	if err != nil {
+		return state, false, err
+	}
+
+	if err := json.Unmarshal(data, &state); err != nil {
+		return state, false, err
+	}
+
+	return state, true, nil
+}
+
+// RemoveGame drops the game saved at path, a saved game is resumed once.
+func RemoveGame(path string) error {
+	if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+		return err
+	}
+
+	return nil
+}
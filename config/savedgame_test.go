@@ -0,0 +1,59 @@
+package config
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/blackprism/goti-snake/game"
+)
+
+func TestSavedGameIsKeptUntilRemoved(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "goti-snake", "game.json")
+
+	saved := game.State{Grid: 15, Snake: []game.Cell{{X: 3, Y: 4}}, Status: game.Pause, Frames: 125}
+	if err := SaveGame(path, saved); err != nil {
+		t.Fatal(err)
+	}
+
+	state, ok, err := LoadGame(path)
+	if err != nil || !ok {
+		t.Fatalf("load: %v %v", ok, err)
+	}
+
+	if state.Grid != 15 || state.Frames != 125 || len(state.Snake) != 1 {
+		t.Fatalf("loaded %+v", state)
+	}
+
+	if _, err := os.Stat(path); err != nil {
+		t.Fatal("loading should keep the file until the game is resumed")
+	}
+
+	if err := RemoveGame(path); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, ok, err := LoadGame(path); ok || err != nil {
+		t.Fatalf("a removed game loaded: %v %v", ok, err)
+	}
+
+	if err := RemoveGame(path); err != nil {
+		t.Fatalf("removing no saved game: %v", err)
+	}
+}
+
+func TestBrokenSavedGameIsKept(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "game.json")
+	if err := ioutil.WriteFile(path, []byte("{broken"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, ok, err := LoadGame(path); ok || err == nil {
+		t.Fatal("a broken saved game should fail to load")
+	}
+
+	if _, err := os.Stat(path); err != nil {
+		t.Fatal("a saved game which failed to load was removed")
+	}
+}
@@ -0,0 +1,64 @@
+package controls
+
+import "fmt"
+
+// Action is what the player asks for, whatever the key pressed.
+type Action int
+
+const (
+	TurnUp          Action = 0
+	TurnDown        Action = 1
+	TurnLeft        Action = 2
+	TurnRight       Action = 3
+	Pause           Action = 4
+	NewGame         Action = 5
+	Screenshot      Action = 6
+	ToggleAutopilot Action = 7
+	ToggleDebug     Action = 8
+)
+
+var actionNames = map[Action]string{
+	TurnUp:          "Turn up",
+	TurnDown:        "Turn down",
+	TurnLeft:        "Turn left",
+	TurnRight:       "Turn right",
+	Pause:           "Pause",
+	NewGame:         "New game",
+	Screenshot:      "Screenshot",
+	ToggleAutopilot: "Toggle autopilot",
+	ToggleDebug:     "Toggle debug overlay",
+}
+
+// Actions returns every action in the order shown to the player.
+func Actions() []Action {
+	actions := make([]Action, len(actionNames))
+	for action := range actionNames {
+		actions[action] = action
+	}
+
+	return actions
+}
+
+func (action Action) String() string {
+	return actionNames[action]
+}
+
+func (action Action) MarshalText() ([]byte, error) {
+	name, ok := actionNames[action]
+	if !ok {
+		return nil, fmt.Errorf("unknown action %d", action)
+	}
+
+	return []byte(name), nil
+}
+
+func (action *Action) UnmarshalText(text []byte) error {
+	for value, name := range actionNames {
+		if name == string(text) {
+			*action = value
+			return nil
+		}
+	}
+
+	return fmt.Errorf("unknown action %q", text)
+}
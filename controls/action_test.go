@@ -0,0 +1,32 @@
+package controls
+
+import "testing"
+
+func TestEveryProfileBindsEveryAction(t *testing.T) {
+	for name, profile := range Profiles {
+		for _, action := range Actions() {
+			if len(profile[action]) == 0 {
+				t.Errorf("%s leaves %s unbound", name, action)
+			}
+		}
+	}
+}
+
+func TestActionNamesRoundTrip(t *testing.T) {
+	for _, action := range Actions() {
+		text, err := action.MarshalText()
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		var decoded Action
+		if err := decoded.UnmarshalText(text); err != nil || decoded != action {
+			t.Errorf("%q decoded to %d, %v", text, decoded, err)
+		}
+	}
+
+	var autopilot Action
+	if err := autopilot.UnmarshalText([]byte("Toggle autopilot")); err != nil || autopilot != ToggleAutopilot {
+		t.Fatalf("autopilot toggle decoded to %d, %v", autopilot, err)
+	}
+}
@@ -0,0 +1,148 @@
+package controls
+
+import (
+	"fmt"
+	"sort"
+
+	rl "github.com/gen2brain/raylib-go/raylib"
+)
+
+// maxKeys is the number of keys an action can be bound to.
+const maxKeys = 3
+
+// Keyboard is the source of key presses, the raylib window or a fake one.
+type Keyboard interface {
+	IsKeyPressed(key int32) bool
+}
+
+// Bindings maps each action to the keys triggering it.
+type Bindings map[Action][]Key
+
+// Profiles are the built-in bindings, Arrows being the default.
+var Profiles = map[string]Bindings{
+	"Arrows": {
+		TurnUp:          {rl.KeyUp},
+		TurnDown:        {rl.KeyDown},
+		TurnLeft:        {rl.KeyLeft},
+		TurnRight:       {rl.KeyRight},
+		Pause:           {rl.KeySpace},
+		NewGame:         {rl.KeyN, rl.KeyEnter, rl.KeyKpEnter},
+		Screenshot:      {rl.KeyF12},
+		ToggleAutopilot: {rl.KeyTab},
+		ToggleDebug:     {rl.KeyF3},
+	},
+	"WASD": {
+		TurnUp:          {rl.KeyW},
+		TurnDown:        {rl.KeyS},
+		TurnLeft:        {rl.KeyA},
+		TurnRight:       {rl.KeyD},
+		Pause:           {rl.KeySpace},
+		NewGame:         {rl.KeyN, rl.KeyEnter},
+		Screenshot:      {rl.KeyF12},
+		ToggleAutopilot: {rl.KeyTab},
+		ToggleDebug:     {rl.KeyF3},
+	},
+	"Vim": {
+		TurnUp:          {rl.KeyK},
+		TurnDown:        {rl.KeyJ},
+		TurnLeft:        {rl.KeyH},
+		TurnRight:       {rl.KeyL},
+		Pause:           {rl.KeySpace},
+		NewGame:         {rl.KeyN, rl.KeyEnter},
+		Screenshot:      {rl.KeyF12},
+		ToggleAutopilot: {rl.KeyTab},
+		ToggleDebug:     {rl.KeyF3},
+	},
+}
+
+const DefaultProfile = "Arrows"
+
+// ProfileNames returns the built-in profiles sorted by name.
+func ProfileNames() []string {
+	var names []string
+	for name := range Profiles {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	return names
+}
+
+// Profile returns a copy of the named built-in bindings, safe to rebind.
+func Profile(name string) (Bindings, error) {
+	profile, ok := Profiles[name]
+	if !ok {
+		return nil, fmt.Errorf("unknown key binding profile %q", name)
+	}
+
+	return profile.Copy(), nil
+}
+
+func (bindings Bindings) Copy() Bindings {
+	copied := make(Bindings, len(bindings))
+	for action, keys := range bindings {
+		copied[action] = append([]Key(nil), keys...)
+	}
+
+	return copied
+}
+
+// Pressed tells whether one of the keys bound to action was pressed.
+func (bindings Bindings) Pressed(keyboard Keyboard, action Action) bool {
+	for _, key := range bindings[action] {
+		if keyboard.IsKeyPressed(int32(key)) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Bind adds key to action, the oldest key is dropped when the action has
+// already maxKeys keys. A key triggers a single action, it is unbound from
+// any other one.
+func (bindings Bindings) Bind(action Action, key Key) {
+	for other, keys := range bindings {
+		for index, bound := range keys {
+			if bound == key {
+				bindings[other] = append(keys[:index:index], keys[index+1:]...)
+				break
+			}
+		}
+	}
+
+	keys := append(bindings[action], key)
+	if len(keys) > maxKeys {
+		keys = keys[len(keys)-maxKeys:]
+	}
+	bindings[action] = keys
+}
+
+// Clear leaves action without a key, it stays in the bindings so that
+// Complete does not bind it again.
+func (bindings Bindings) Clear(action Action) {
+	bindings[action] = nil
+}
+
+// Complete binds the actions missing from the bindings, added since they
+// were saved, to their keys in profile which are still free.
+func (bindings Bindings) Complete(profile Bindings) {
+	bound := map[Key]bool{}
+	for _, keys := range bindings {
+		for _, key := range keys {
+			bound[key] = true
+		}
+	}
+
+	for action, keys := range profile {
+		if _, ok := bindings[action]; ok {
+			continue
+		}
+
+		for _, key := range keys {
+			if !bound[key] {
+				bindings[action] = append(bindings[action], key)
+			}
+		}
+	}
+}
@@ -0,0 +1,234 @@
+package controls
+
+import (
+	"math"
+
+	rl "github.com/gen2brain/raylib-go/raylib"
+)
+
+// Buttons and axes of the generic gamepad layout of raylib 3, the bindings
+// only expose the older per-vendor codes.
+const (
+	buttonUp     int32 = 1
+	buttonRight  int32 = 2
+	buttonDown   int32 = 3
+	buttonLeft   int32 = 4
+	buttonA      int32 = 7
+	buttonB      int32 = 6
+	buttonSelect int32 = 13
+	buttonStart  int32 = 15
+
+	axisLeftX int32 = 0
+	axisLeftY int32 = 1
+)
+
+// The left stick engages a direction past stickPress and lets it go under
+// stickRelease, another direction takes over only once it leads by
+// stickSwitch so that diagonals don't jitter between two turns.
+const (
+	stickPress   = 0.6
+	stickRelease = 0.4
+	stickSwitch  = 0.25
+)
+
+const MaxGamepads = 4
+
+// GamepadInput is the raylib gamepad API, a fake one in tests.
+type GamepadInput interface {
+	IsGamepadAvailable(gamepad int32) bool
+	IsGamepadButtonPressed(gamepad int32, button int32) bool
+	GetGamepadAxisMovement(gamepad int32, axis int32) float32
+}
+
+// Raylib reads the gamepads of the window.
+type Raylib struct{}
+
+func (input Raylib) IsGamepadAvailable(gamepad int32) bool {
+	return rl.IsGamepadAvailable(gamepad)
+}
+
+func (input Raylib) IsGamepadButtonPressed(gamepad int32, button int32) bool {
+	return rl.IsGamepadButtonPressed(gamepad, button)
+}
+
+func (input Raylib) GetGamepadAxisMovement(gamepad int32, axis int32) float32 {
+	return rl.GetGamepadAxisMovement(gamepad, axis)
+}
+
+var gamepadButtons = map[Action]int32{
+	TurnUp:          buttonUp,
+	TurnDown:        buttonDown,
+	TurnLeft:        buttonLeft,
+	TurnRight:       buttonRight,
+	Pause:           buttonStart,
+	NewGame:         buttonA,
+	ToggleAutopilot: buttonSelect,
+}
+
+const noAction Action = -1
+
+// Gamepad maps one gamepad onto the actions, Update has to be called once
+// per frame before asking for the actions.
+type Gamepad struct {
+	ID    int32
+	input GamepadInput
+	held  Action
+	moved Action
+}
+
+func NewGamepad(id int32, input GamepadInput) *Gamepad {
+	return &Gamepad{
+		ID:    id,
+		input: input,
+		held:  noAction,
+		moved: noAction,
+	}
+}
+
+func (gamepad *Gamepad) Available() bool {
+	return gamepad.input.IsGamepadAvailable(gamepad.ID)
+}
+
+func (gamepad *Gamepad) Update() {
+	gamepad.moved = noAction
+
+	if !gamepad.Available() {
+		gamepad.held = noAction
+		return
+	}
+
+	x := gamepad.input.GetGamepadAxisMovement(gamepad.ID, axisLeftX)
+	y := gamepad.input.GetGamepadAxisMovement(gamepad.ID, axisLeftY)
+	leading, lead := stickDirection(x, y)
+
+	if gamepad.held != noAction {
+		current := stickValue(gamepad.held, x, y)
+		if current >= stickRelease {
+			if leading != gamepad.held && lead > current+stickSwitch {
+				gamepad.held = leading
+				gamepad.moved = leading
+			}
+
+			return
+		}
+
+		gamepad.held = noAction
+	}
+
+	if lead >= stickPress {
+		gamepad.held = leading
+		gamepad.moved = leading
+	}
+}
+
+// Pressed tells whether action was triggered by a button or by the stick
+// engaging a direction.
+func (gamepad *Gamepad) Pressed(action Action) bool {
+	if gamepad.moved == action {
+		return true
+	}
+
+	button, ok := gamepadButtons[action]
+
+	return ok && gamepad.Available() && gamepad.input.IsGamepadButtonPressed(gamepad.ID, button)
+}
+
+// Confirmed and Cancelled are the A and B buttons in the menus.
+func (gamepad *Gamepad) Confirmed() bool {
+	return gamepad.Available() && gamepad.input.IsGamepadButtonPressed(gamepad.ID, buttonA)
+}
+
+func (gamepad *Gamepad) Cancelled() bool {
+	return gamepad.Available() && gamepad.input.IsGamepadButtonPressed(gamepad.ID, buttonB)
+}
+
+// stickDirection returns the direction the stick leans the most to and how
+// far, y growing downward.
+func stickDirection(x float32, y float32) (Action, float32) {
+	if math.Abs(float64(x)) >= math.Abs(float64(y)) {
+		if x < 0 {
+			return TurnLeft, -x
+		}
+		return TurnRight, x
+	}
+
+	if y < 0 {
+		return TurnUp, -y
+	}
+
+	return TurnDown, y
+}
+
+func stickValue(action Action, x float32, y float32) float32 {
+	switch action {
+	case TurnUp:
+		return -y
+	case TurnDown:
+		return y
+	case TurnLeft:
+		return -x
+	case TurnRight:
+		return x
+	}
+
+	return 0
+}
+
+// Gamepads are the gamepads of the players, the first one being player one.
+type Gamepads []*Gamepad
+
+func NewGamepads(input GamepadInput) Gamepads {
+	var gamepads Gamepads
+	for id := int32(0); id < MaxGamepads; id++ {
+		gamepads = append(gamepads, NewGamepad(id, input))
+	}
+
+	return gamepads
+}
+
+func (gamepads Gamepads) Update() {
+	for _, gamepad := range gamepads {
+		gamepad.Update()
+	}
+}
+
+// Player returns the gamepad of the player starting at 0, nil when it is
+// not connected.
+func (gamepads Gamepads) Player(player int) *Gamepad {
+	if player < len(gamepads) && gamepads[player].Available() {
+		return gamepads[player]
+	}
+
+	return nil
+}
+
+// Pressed tells whether any connected gamepad triggered action.
+func (gamepads Gamepads) Pressed(action Action) bool {
+	for _, gamepad := range gamepads {
+		if gamepad.Pressed(action) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (gamepads Gamepads) Confirmed() bool {
+	for _, gamepad := range gamepads {
+		if gamepad.Confirmed() {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (gamepads Gamepads) Cancelled() bool {
+	for _, gamepad := range gamepads {
+		if gamepad.Cancelled() {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,110 @@
+package controls
+
+import "testing"
+
+// fakeGamepad is a single gamepad with its left stick at x, y.
+type fakeGamepad struct {
+	x       float32
+	y       float32
+	pressed map[int32]bool
+}
+
+func (input *fakeGamepad) IsGamepadAvailable(gamepad int32) bool {
+	return gamepad == 0
+}
+
+func (input *fakeGamepad) IsGamepadButtonPressed(gamepad int32, button int32) bool {
+	return input.pressed[button]
+}
+
+func (input *fakeGamepad) GetGamepadAxisMovement(gamepad int32, axis int32) float32 {
+	if axis == axisLeftX {
+		return input.x
+	}
+
+	return input.y
+}
+
+// tilt moves the stick and tells which turn the gamepad triggered, -1 for
+// none.
+func tilt(gamepad *Gamepad, input *fakeGamepad, x float32, y float32) Action {
+	input.x, input.y = x, y
+	gamepad.Update()
+
+	for _, action := range []Action{TurnUp, TurnDown, TurnLeft, TurnRight} {
+		if gamepad.Pressed(action) {
+			return action
+		}
+	}
+
+	return noAction
+}
+
+func TestStickDeadzone(t *testing.T) {
+	input := &fakeGamepad{}
+	gamepad := NewGamepad(0, input)
+
+	if action := tilt(gamepad, input, 0.5, 0); action != noAction {
+		t.Fatalf("a stick under the press threshold turned %s", action)
+	}
+
+	if action := tilt(gamepad, input, 0.7, 0); action != TurnRight {
+		t.Fatalf("pushing the stick right turned %s", action)
+	}
+
+	if action := tilt(gamepad, input, 0.7, 0); action != noAction {
+		t.Fatalf("holding the stick turned %s again", action)
+	}
+}
+
+func TestStickHysteresis(t *testing.T) {
+	input := &fakeGamepad{}
+	gamepad := NewGamepad(0, input)
+
+	tilt(gamepad, input, 0, -0.7)
+
+	// Easing off above the release threshold keeps the direction held.
+	tilt(gamepad, input, 0, -0.45)
+	if action := tilt(gamepad, input, 0, -0.7); action != noAction {
+		t.Fatalf("the stick was not released but turned %s", action)
+	}
+
+	// Under it, the next push is a new turn.
+	tilt(gamepad, input, 0, -0.3)
+	if action := tilt(gamepad, input, 0, -0.7); action != TurnUp {
+		t.Fatalf("pushing up again turned %s", action)
+	}
+}
+
+func TestStickSwitch(t *testing.T) {
+	input := &fakeGamepad{}
+	gamepad := NewGamepad(0, input)
+
+	tilt(gamepad, input, 0, 0.7)
+
+	// A diagonal leaning a bit more to the left doesn't switch.
+	if action := tilt(gamepad, input, -0.75, 0.6); action != noAction {
+		t.Fatalf("a diagonal turned %s", action)
+	}
+
+	if action := tilt(gamepad, input, -0.9, 0.5); action != TurnLeft {
+		t.Fatalf("leading left by the switch margin turned %s", action)
+	}
+}
+
+func TestGamepadButtons(t *testing.T) {
+	input := &fakeGamepad{pressed: map[int32]bool{buttonStart: true}}
+	gamepad := NewGamepad(0, input)
+	gamepad.Update()
+
+	if !gamepad.Pressed(Pause) || gamepad.Pressed(NewGame) {
+		t.Fatal("start should pause and only pause")
+	}
+
+	missing := NewGamepad(1, input)
+	missing.Update()
+
+	if missing.Pressed(Pause) || missing.Available() {
+		t.Fatal("a disconnected gamepad pressed a button")
+	}
+}
@@ -0,0 +1,84 @@
+package controls
+
+import (
+	"fmt"
+	"strconv"
+
+	rl "github.com/gen2brain/raylib-go/raylib"
+)
+
+// Key is a raylib key code saved by name in the config file.
+type Key int32
+
+var keyNames = map[Key]string{
+	rl.KeySpace:     "Space",
+	rl.KeyEscape:    "Escape",
+	rl.KeyEnter:     "Enter",
+	rl.KeyTab:       "Tab",
+	rl.KeyBackspace: "Backspace",
+	rl.KeyInsert:    "Insert",
+	rl.KeyDelete:    "Delete",
+	rl.KeyRight:     "Right",
+	rl.KeyLeft:      "Left",
+	rl.KeyDown:      "Down",
+	rl.KeyUp:        "Up",
+	rl.KeyPageUp:    "PageUp",
+	rl.KeyPageDown:  "PageDown",
+	rl.KeyHome:      "Home",
+	rl.KeyEnd:       "End",
+	rl.KeyPause:     "Pause",
+	rl.KeyKpEnter:   "KpEnter",
+	rl.KeyKp2:       "Kp2",
+	rl.KeyKp4:       "Kp4",
+	rl.KeyKp6:       "Kp6",
+	rl.KeyKp8:       "Kp8",
+	rl.KeyComma:     "Comma",
+	rl.KeyPeriod:    "Period",
+	rl.KeySemicolon: "Semicolon",
+	rl.KeySlash:     "Slash",
+	rl.KeyMinus:     "Minus",
+	rl.KeyEqual:     "Equal",
+}
+
+func init() {
+	for key := Key(rl.KeyA); key <= rl.KeyZ; key++ {
+		keyNames[key] = string(rune(key))
+	}
+
+	for key := Key(rl.KeyZero); key <= rl.KeyNine; key++ {
+		keyNames[key] = string(rune(key))
+	}
+
+	for key := Key(rl.KeyF1); key <= rl.KeyF12; key++ {
+		keyNames[key] = "F" + strconv.Itoa(int(key-rl.KeyF1+1))
+	}
+}
+
+func (key Key) String() string {
+	if name, ok := keyNames[key]; ok {
+		return name
+	}
+
+	return fmt.Sprintf("Key%d", int32(key))
+}
+
+func (key Key) MarshalText() ([]byte, error) {
+	return []byte(key.String()), nil
+}
+
+func (key *Key) UnmarshalText(text []byte) error {
+	for value, name := range keyNames {
+		if name == string(text) {
+			*key = value
+			return nil
+		}
+	}
+
+	var code int32
+	if _, err := fmt.Sscanf(string(text), "Key%d", &code); err != nil {
+		return fmt.Errorf("unknown key %q", text)
+	}
+	*key = Key(code)
+
+	return nil
+}
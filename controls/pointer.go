@@ -0,0 +1,58 @@
+package controls
+
+import rl "github.com/gen2brain/raylib-go/raylib"
+
+// PointerInput is the raylib mouse and touch gestures API, a fake one in
+// tests. A mouse click is a tap for raylib.
+type PointerInput interface {
+	IsGestureDetected(gesture rl.Gestures) bool
+	GetTouchPosition(index int32) rl.Vector2
+}
+
+// Raylib reads the pointer of the window too.
+func (input Raylib) IsGestureDetected(gesture rl.Gestures) bool {
+	return rl.IsGestureDetected(gesture)
+}
+
+func (input Raylib) GetTouchPosition(index int32) rl.Vector2 {
+	return rl.GetTouchPosition(index)
+}
+
+var swipes = map[Action]rl.Gestures{
+	TurnUp:    rl.GestureSwipeUp,
+	TurnDown:  rl.GestureSwipeDown,
+	TurnLeft:  rl.GestureSwipeLeft,
+	TurnRight: rl.GestureSwipeRight,
+}
+
+// Pointer maps swipes onto the turn actions and tells where the player
+// tapped or clicked.
+type Pointer struct {
+	input PointerInput
+}
+
+func NewPointer(input PointerInput) *Pointer {
+	return &Pointer{input: input}
+}
+
+// Pressed tells whether the player swiped toward the turn action.
+func (pointer *Pointer) Pressed(action Action) bool {
+	if pointer == nil {
+		return false
+	}
+
+	gesture, ok := swipes[action]
+
+	return ok && pointer.input.IsGestureDetected(gesture)
+}
+
+// Tapped returns the pixel tapped or clicked this frame.
+func (pointer *Pointer) Tapped() (int32, int32, bool) {
+	if pointer == nil || !pointer.input.IsGestureDetected(rl.GestureTap) {
+		return 0, 0, false
+	}
+
+	position := pointer.input.GetTouchPosition(0)
+
+	return int32(position.X), int32(position.Y), true
+}
@@ -0,0 +1,49 @@
+package controls
+
+import (
+	"testing"
+
+	rl "github.com/gen2brain/raylib-go/raylib"
+)
+
+type fakePointer struct {
+	gesture  rl.Gestures
+	position rl.Vector2
+}
+
+func (input *fakePointer) IsGestureDetected(gesture rl.Gestures) bool {
+	return input.gesture == gesture
+}
+
+func (input *fakePointer) GetTouchPosition(index int32) rl.Vector2 {
+	return input.position
+}
+
+func TestSwipeTurns(t *testing.T) {
+	pointer := NewPointer(&fakePointer{gesture: rl.GestureSwipeLeft})
+
+	if !pointer.Pressed(TurnLeft) || pointer.Pressed(TurnRight) || pointer.Pressed(Pause) {
+		t.Fatal("a left swipe should only turn left")
+	}
+
+	if _, _, ok := pointer.Tapped(); ok {
+		t.Fatal("a swipe is not a tap")
+	}
+}
+
+func TestTapped(t *testing.T) {
+	pointer := NewPointer(&fakePointer{gesture: rl.GestureTap, position: rl.NewVector2(120.6, 45)})
+
+	x, y, ok := pointer.Tapped()
+	if !ok || x != 120 || y != 45 {
+		t.Fatalf("tapped %d,%d %v, want 120,45", x, y, ok)
+	}
+}
+
+func TestNilPointer(t *testing.T) {
+	var pointer *Pointer
+
+	if _, _, ok := pointer.Tapped(); ok || pointer.Pressed(TurnUp) {
+		t.Fatal("without a pointer nothing is pressed")
+	}
+}
@@ -0,0 +1,45 @@
+package controls
+
+import "fmt"
+
+// Steering is how the turn actions steer the snake: toward a side of the
+// board, or with only turn left and turn right relative to the snake.
+type Steering int
+
+const (
+	Absolute Steering = 0
+	Relative Steering = 1
+)
+
+var steeringNames = map[Steering]string{
+	Absolute: "Absolute",
+	Relative: "Relative",
+}
+
+func (steering Steering) String() string {
+	return steeringNames[steering]
+}
+
+func (steering Steering) Next() Steering {
+	return (steering + 1) % Steering(len(steeringNames))
+}
+
+func (steering Steering) MarshalText() ([]byte, error) {
+	name, ok := steeringNames[steering]
+	if !ok {
+		return nil, fmt.Errorf("unknown steering %d", steering)
+	}
+
+	return []byte(name), nil
+}
+
+func (steering *Steering) UnmarshalText(text []byte) error {
+	for value, name := range steeringNames {
+		if name == string(text) {
+			*steering = value
+			return nil
+		}
+	}
+
+	return fmt.Errorf("unknown steering %q", text)
+}
@@ -0,0 +1,120 @@
+package game
+
+var steps = map[Direction]Position{
+	Up:    {0, -1},
+	Down:  {0, 1},
+	Left:  {-1, 0},
+	Right: {1, 0},
+}
+
+var directions = []Direction{Up, Right, Down, Left}
+
+var opposites = map[Direction]Direction{
+	Up:    Down,
+	Down:  Up,
+	Left:  Right,
+	Right: Left,
+}
+
+func (board *Board) SetAutopilot(enabled bool) {
+	board.autopilot = enabled
+}
+
+func (board *Board) Autopilot() bool {
+	return board.autopilot
+}
+
+// steer points the snake to the closest food through a breadth first search
+// on the free cells, or to any free cell when no food can be reached. It
+// turns like the player does so the turn is heard, and never turns back.
+func (board *Board) steer() {
+	blocked := make([][]bool, board.grid)
+	for x := range blocked {
+		blocked[x] = make([]bool, board.grid)
+	}
+
+	// The tail leaves its cell while the head moves, it is not an obstacle.
+	for index := board.snake.head; index > board.snake.tail(); index-- {
+		position := board.snake.getBody(index)
+		if board.inside(position) {
+			blocked[position.x][position.y] = true
+		}
+	}
+
+	for _, hazard := range board.hazards {
+		if board.inside(hazard.position) {
+			blocked[hazard.position.x][hazard.position.y] = true
+		}
+	}
+
+	type visit struct {
+		position Position
+		first    Direction
+	}
+
+	var queue []visit
+	fallback := -1
+	head := board.snake.getBody(board.snake.head)
+
+	for _, direction := range directions {
+		if direction == opposites[board.snake.direction] {
+			continue
+		}
+
+		next, ok := board.step(head, direction)
+		if !ok || blocked[next.x][next.y] {
+			continue
+		}
+
+		if board.hasFoodAt(next.x, next.y) {
+			board.Turn(direction)
+			return
+		}
+
+		if fallback < 0 {
+			fallback = int(direction)
+		}
+		blocked[next.x][next.y] = true
+		queue = append(queue, visit{position: next, first: direction})
+	}
+
+	for len(queue) > 0 {
+		current := queue[0]
+		queue = queue[1:]
+
+		for _, direction := range directions {
+			next, ok := board.step(current.position, direction)
+			if !ok || blocked[next.x][next.y] {
+				continue
+			}
+
+			if board.hasFoodAt(next.x, next.y) {
+				board.Turn(current.first)
+				return
+			}
+
+			blocked[next.x][next.y] = true
+			queue = append(queue, visit{position: next, first: current.first})
+		}
+	}
+
+	if fallback >= 0 {
+		board.Turn(Direction(fallback))
+	}
+}
+
+// step returns the cell next to position in direction, crossing the walls
+// when they wrap.
+func (board *Board) step(position Position, direction Direction) (Position, bool) {
+	next := newPosition(position.x+steps[direction].x, position.y+steps[direction].y)
+
+	if board.wallMode == WrappedWalls || board.mode == Zen {
+		return newPosition((next.x+board.grid)%board.grid, (next.y+board.grid)%board.grid), true
+	}
+
+	return next, board.inside(next)
+}
+
+func (board *Board) inside(position Position) bool {
+	return position.x >= 0 && position.y >= 0 && position.x < board.grid && position.y < board.grid
+}
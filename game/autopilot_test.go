@@ -0,0 +1,43 @@
+package game
+
+import "testing"
+
+func TestAutopilotTurnsTowardTheFood(t *testing.T) {
+	board := crowdedBoard()
+	board.hazards = nil
+	board.powerUps = nil
+	board.foods = []Food{newFood(NormalApple, 0, 3)}
+
+	var events []Event
+	board.SetListener(func(event Event) {
+		events = append(events, event)
+	})
+
+	board.steer()
+
+	if board.snake.direction != Down {
+		t.Fatalf("direction = %s, want down", board.snake.direction)
+	}
+
+	if len(events) != 1 || events[0] != Turned {
+		t.Fatalf("events = %v, want one turn", events)
+	}
+}
+
+func TestAutopilotGoesAroundHazards(t *testing.T) {
+	board := crowdedBoard()
+	board.powerUps = nil
+	board.hazards = []Hazard{{position: newPosition(0, 1), kind: Bomb, ticksLeft: 10}}
+	board.foods = []Food{newFood(NormalApple, 0, 3)}
+	board.snake.Load([]Cell{{X: 0, Y: 0}}, Down)
+
+	board.steer()
+
+	if board.snake.direction != Right {
+		t.Fatalf("direction = %s, the bomb is down", board.snake.direction)
+	}
+
+	if board.Status() == GameOver {
+		t.Fatal("steering should never turn back")
+	}
+}
@@ -1,40 +1,72 @@
 package game
 
 import (
+	"fmt"
 	"math/rand"
 	"time"
 
 	rl "github.com/gen2brain/raylib-go/raylib"
 )
 
+const FramesPerSecond = 240
+
 func NewBoard(snake *Snake, size int32, grid int32, border int32, speed float32, position CoordinateConverter) *Board {
 	return &Board{
+		cellSize:   (size - 2*border) / grid,
+		nextGrid:   grid,
 		snake:      snake,
 		size:       size,
 		grid:       grid,
 		speed:      speed,
+		difficulty: Normal,
+		next:       gameplay{difficulty: Normal},
 		menuSize:   40,
 		border:     border,
 		frames:     0,
 		position:   position,
 		status:     NewGame,
-		lastStatus: NewGame,
+		theme:      ClassicTheme,
 	}
 }
 
 type Board struct {
-	snake      *Snake
-	size       int32
-	grid       int32
-	speed      float32
-	menuSize   int32
-	border     int32
-	frames     int32
-	position   CoordinateConverter
-	apple      Apple
-	cellSize   int32
-	status     Status
-	lastStatus Status
+	snake           *Snake
+	size            int32
+	grid            int32
+	speed           float32
+	curve           SpeedCurve
+	difficulty      Difficulty
+	moveFrames      int32
+	ticks           int
+	mode            GameMode
+	applesEaten     int
+	tailCut         bool
+	dropCutSegments bool
+	wallMode        WallMode
+	theme           Theme
+	shapeCues       bool
+	sprites         bool
+	smooth          bool
+	juice           juice
+	autopilot       bool
+	debug           bool
+	listen          func(Event)
+	nextGrid        int32
+	next            gameplay
+	menuSize        int32
+	border          int32
+	frames          int32
+	position        CoordinateConverter
+	foods           []Food
+	speedTicks      int
+	hazards         []Hazard
+	hazardTicks     int
+	deathCause      DeathCause
+	powerUps        []PowerUp
+	powerUpTicks    int
+	effects         []Effect
+	cellSize        int32
+	status          Status
 }
 
 type Apple struct {
@@ -55,161 +87,269 @@ const (
 func (board *Board) Init() {
 	rand.Seed(time.Now().UnixNano())
 
-	board.cellSize = (board.size - 2*board.border) / board.grid
 	rl.SetConfigFlags(rl.FlagMsaa4xHint)
 	rl.InitWindow(board.size, board.size+board.menuSize, "Goti Board")
-	rl.SetTargetFPS(240)
+	rl.SetTargetFPS(FramesPerSecond)
+	rl.SetExitKey(0)
 }
 
 func (board *Board) Reset() {
 	board.frames = 0
+	board.moveFrames = 0
+	board.ticks = 0
+	board.applesEaten = 0
+	board.foods = nil
+	board.speedTicks = 0
+	board.hazards = nil
+	board.hazardTicks = 0
+	board.deathCause = Alive
+	board.powerUps = nil
+	board.powerUpTicks = 0
+	board.effects = nil
 	board.snake.Init()
+	board.accelerate()
 }
 
 func (board *Board) Draw() {
-	rl.ClearBackground(rl.White)
+	rl.ClearBackground(board.theme.Background)
 	board.drawMenu()
+
+	if board.juice.enabled {
+		board.updateJuice(rl.GetFrameTime())
+		board.beginShake()
+		defer board.endShake()
+	}
+
 	board.drawBackground()
-	board.drawApple()
-	board.snake.Draw(board.cellSize)
+	board.atlas()
+	board.drawFoods()
+	board.drawHazards()
+	board.drawPowerUps()
+
+	board.snake.SetOpacity(255)
+	if board.HasEffect(Ghost) {
+		board.snake.SetOpacity(ghostOpacity)
+	}
 
-	board.frames++
-}
+	if board.smooth && board.snake.atlas == nil {
+		board.snake.drawSmooth(board.cellSize, board.moveFraction())
+	} else {
+		board.snake.Draw(board.cellSize)
+	}
+	board.drawShield()
 
-func (board *Board) CheckApple() {
-	if board.snake.AppleEatable(board.apple) {
-		board.snake.AppleEated(board.apple)
-		board.SpawnApple()
+	if board.juice.enabled {
+		board.drawJuice()
+	}
+
+	if board.debug {
+		board.drawDebug()
 	}
 }
 
 func (board *Board) AutoMove() {
-	if board.frames%int32(60*board.speed) == 0 {
+	board.moveFrames++
+
+	if board.moveFrames >= board.moveInterval() {
+		board.moveFrames = 0
+		if board.autopilot {
+			board.steer()
+		}
 		board.snake.Move()
+		board.ticks++
+		board.rules().Moved(board)
+
+		board.tickFoods()
+		board.tickHazards()
+		board.tickPowerUps()
+		board.CheckHazards()
+		board.CheckPowerUps()
+		board.accelerate()
 	}
 }
 
-func (board *Board) KeyListener() {
-	if rl.IsKeyPressed(rl.KeyN) || rl.IsKeyPressed(rl.KeyEnter) || rl.IsKeyPressed(rl.KeyKpEnter) {
-		board.NewGame()
-		board.status = Continue
-		return
+func (board *Board) moveInterval() int32 {
+	speed := board.speed
+
+	if board.speedTicks > 0 {
+		speed *= speedFruitRatio
 	}
 
-	if board.status == NewGame {
-		return
+	if board.HasEffect(SlowMotion) {
+		speed *= slowMotionRatio
 	}
 
-	if rl.IsKeyPressed(rl.KeySpace) {
-		if board.status == Pause {
-			board.status = Continue
-			return
-		}
+	interval := int32(60 * speed)
+	if interval < 1 {
+		return 1
+	}
+
+	return interval
+}
+
+func (board *Board) Start() {
+	board.NewGame()
+	board.status = Continue
+}
+
+func (board *Board) Stop() {
+	board.status = NewGame
+}
 
+func (board *Board) SetPaused(paused bool) {
+	if paused {
 		board.status = Pause
 		return
 	}
 
-	if rl.IsKeyPressed(rl.KeyRight) {
-		if !board.snake.GoingToDirection(Right) {
-			board.status = GameOver
-			return
-		}
+	board.status = Continue
+}
+
+func (board *Board) Turn(direction Direction) bool {
+	previous := board.snake.direction
+	if !board.snake.GoingToDirection(direction) {
+		board.die(TurnedBack)
+		return false
 	}
 
-	if rl.IsKeyPressed(rl.KeyLeft) {
-		if !board.snake.GoingToDirection(Left) {
-			board.status = GameOver
-			return
-		}
+	if direction != previous {
+		board.notify(Turned)
 	}
 
-	if rl.IsKeyPressed(rl.KeyUp) {
-		if !board.snake.GoingToDirection(Up) {
-			board.status = GameOver
-			return
-		}
+	return true
+}
+
+// TurnToward turns the snake toward the side of the head where the pixel
+// at x, y lies, along the axis it is the farthest on. It never turns back,
+// the other axis is used instead, and returns false when it did not turn.
+func (board *Board) TurnToward(x int32, y int32) bool {
+	head := board.snake.getBody(board.snake.head)
+	dx := board.position.PixelToX(x) - head.x
+	dy := board.position.PixelToY(y) - head.y
+
+	horizontal := Right
+	if dx < 0 {
+		horizontal = Left
+	}
+
+	vertical := Down
+	if dy < 0 {
+		vertical = Up
+	}
+
+	if dx == 0 && dy == 0 {
+		return false
 	}
 
-	if rl.IsKeyPressed(rl.KeyDown) {
-		if !board.snake.GoingToDirection(Down) {
-			board.status = GameOver
-			return
+	candidates := []Direction{horizontal, vertical}
+	if abs(dy) > abs(dx) {
+		candidates = []Direction{vertical, horizontal}
+	}
+
+	for _, direction := range candidates {
+		if (direction == horizontal && dx == 0) || (direction == vertical && dy == 0) {
+			continue
+		}
+
+		if direction != opposites[board.snake.heading] && direction != opposites[board.snake.direction] {
+			return board.Turn(direction)
 		}
 	}
+
+	return false
+}
+
+// Steer turns the snake relative to where it goes, it can't turn back.
+func (board *Board) Steer(clockwise bool) {
+	previous := board.snake.direction
+	board.snake.Steer(clockwise)
+
+	if board.snake.direction != previous {
+		board.notify(Turned)
+	}
+}
+
+func (board *Board) Running() bool {
+	return board.status == Continue || board.status == Pause
+}
+
+func (board *Board) Status() Status {
+	return board.status
+}
+
+func (board *Board) Score() int {
+	return board.rules().Score(board)
 }
 
 func (board *Board) drawMenu() {
 	rl.DrawFPS(10, 10)
+	board.drawEffects()
+	board.drawSpeed()
+	board.drawMode()
 }
 
-func (board *Board) drawBackground() {
-	rl.DrawRectangle(0, board.menuSize, board.size, board.size, rl.NewColor(66, 66, 66, 255))
-	rl.DrawRectangle(board.border, board.menuSize+board.border, board.size-2*board.border, board.size-2*board.border, rl.White)
+func (board *Board) drawMode() {
+	text := fmt.Sprintf("%s  %s", board.mode, board.rules().Hud(board))
+	if board.autopilot {
+		text = "Autopilot  " + text
+	}
+	rl.DrawText(text, board.size-190-rl.MeasureText(text, 16), 12, 16, board.theme.Text)
 }
 
-func (board *Board) drawApple() {
-	rl.DrawRectangle(
-		board.position.XToPixel(board.apple.x),
-		board.position.YToPixel(board.apple.y),
-		board.cellSize,
-		board.cellSize,
-		rl.NewColor(192, 70, 67, 255),
-	)
+func (board *Board) drawSpeed() {
+	rl.DrawText(fmt.Sprintf("%s %.1f cells/s", board.difficulty, board.CellsPerSecond()), board.size-170, 12, 16, board.theme.Text)
 }
 
-func (board *Board) SpawnApple() {
-	freeCells := board.snake.GetFreeCells()
+func (board *Board) drawBackground() {
+	rl.DrawRectangle(0, board.menuSize, board.size, board.size, board.theme.Border)
+	rl.DrawRectangle(board.border, board.menuSize+board.border, board.size-2*board.border, board.size-2*board.border, board.theme.Floor)
 
-	if len(freeCells) == 0 {
+	if board.theme.GridLines.A == 0 {
 		return
 	}
 
-	freeCell := freeCells[rand.Intn(len(freeCells))]
-	board.apple = Apple{
-		x: freeCell[0],
-		y: freeCell[1],
+	for cell := int32(1); cell < board.grid; cell++ {
+		x := board.position.XToPixel(cell)
+		y := board.position.YToPixel(cell)
+		rl.DrawLine(x, board.position.YToPixel(0), x, board.position.YToPixel(board.grid), board.theme.GridLines)
+		rl.DrawLine(board.position.XToPixel(0), y, board.position.XToPixel(board.grid), y, board.theme.GridLines)
 	}
 }
 
 func (board *Board) GetGameStatus() Status {
-	if board.snake.Size() == int(board.grid*board.grid)+1 {
-		board.status = Victory
-		return board.status
-	}
+	previous := board.status
+	board.status = board.rules().Status(board)
 
-	if board.snake.IsOutside(0, 0, board.grid, board.grid) || board.snake.IsEatingItSelf() {
-		board.status = GameOver
-		return board.status
+	if board.status == Victory && previous != Victory {
+		board.celebrate()
+		board.notify(Won)
 	}
 
-	board.status = Continue
 	return board.status
 }
 
-func (board *Board) Loop() Status {
-	board.KeyListener()
-
-	if board.status == NewGame {
-		board.Draw()
-		switch board.lastStatus {
-		case Victory:
-			board.DisplayVictory()
-		case GameOver:
-			board.DisplayGameOver()
-		}
-
-		board.DisplayAskNewGame()
+// deadlyStatus applies the ways of dying shared by the game modes.
+func (board *Board) deadlyStatus() Status {
+	if board.deathCause != Alive {
+		return GameOver
+	}
 
+	if board.snake.IsOutside(0, 0, board.grid, board.grid) {
+		board.die(HitWall)
 		return board.status
 	}
 
-	if board.status == Pause {
-		board.DisplayPause()
-
+	if !board.HasEffect(Ghost) && board.snake.IsEatingItSelf() {
+		board.die(AteItself)
 		return board.status
 	}
 
+	return Continue
+}
+
+// Tick runs the rules for one frame without drawing anything, it is all a
+// frontend without a raylib window needs to drive a game.
+func (board *Board) Tick() Status {
 	if board.status != Continue {
 		return board.status
 	}
@@ -218,39 +358,68 @@ func (board *Board) Loop() Status {
 		return board.status
 	}
 
-	board.Draw()
-	board.CheckApple()
+	board.frames++
+	board.CheckFood()
 	board.AutoMove()
 
 	return board.GetGameStatus()
 }
 
 func (board *Board) NewGame() {
+	if board.nextGrid != board.grid {
+		board.resize(board.nextGrid)
+	}
+
+	board.applyGameplay()
 	board.Reset()
-	board.SpawnApple()
+	board.SpawnFood()
 }
 
-func (board *Board) AskNewGame() {
-	board.status = NewGame
-	board.DisplayAskNewGame()
+// SetGrid changes the number of cells per side, it only applies on the next
+// new game as the running one is laid out on the current grid.
+func (board *Board) SetGrid(grid int32) {
+	board.nextGrid = grid
+}
+
+func (board *Board) Grid() int32 {
+	return board.nextGrid
 }
 
-func (board *Board) DisplayAskNewGame() {
-	rl.DrawText("Press [ENTER] for a New game", board.size/2-270, 80, 20, rl.Black)
+func (board *Board) resize(grid int32) {
+	board.grid = grid
+	board.cellSize = (board.size - 2*board.border) / grid
+	board.position = NewCoordinateConverter(board.border, board.menuSize+board.border, board.cellSize)
+	board.snake.resize(grid, board.position)
 }
 
 func (board *Board) DisplayVictory() {
-	board.lastStatus = Victory
-	board.status = NewGame
-	rl.DrawText("Victory !", board.size/2-150, 2, 40, rl.Black)
+	rl.DrawText("Victory !", board.size/2-150, 2, 40, board.theme.Text)
 }
 
 func (board *Board) DisplayGameOver() {
-	board.lastStatus = GameOver
-	board.status = NewGame
-	rl.DrawText("You lose !", board.size/2-150, 2, 40, rl.Black)
+	rl.DrawText("You lose !", board.size/2-150, 2, 40, board.theme.Text)
+	board.displayDeathCause()
+}
+
+func (board *Board) displayDeathCause() {
+	rl.DrawText(board.deathCause.Message(), board.size/2-270, 110, 20, rl.Maroon)
 }
 
 func (board *Board) DisplayPause() {
-	rl.DrawText("Pause", board.size/2-150, 2, 40, rl.Black)
+	rl.DrawText("Pause", board.size/2-150, 2, 40, board.theme.Text)
+}
+
+func (board *Board) DisplaySpectating() {
+	switch board.status {
+	case Victory:
+		board.DisplayVictory()
+	case GameOver:
+		board.DisplayGameOver()
+	case Pause:
+		board.DisplayPause()
+	case NewGame:
+		rl.DrawText("Waiting for a new game", board.size/2-150, 10, 20, board.theme.Text)
+	}
+
+	rl.DrawText("Spectating", board.size-130, board.menuSize+board.size-board.border, 20, rl.LightGray)
 }
@@ -21,3 +21,23 @@ func (position *CoordinateConverter) XToPixel(x int32) int32 {
 func (position *CoordinateConverter) YToPixel(y int32) int32 {
 	return position.borderY + y*position.grid
 }
+
+// PixelToX is the inverse of XToPixel, any pixel of a cell gives the cell.
+func (position *CoordinateConverter) PixelToX(x int32) int32 {
+	return floorDiv(x-position.borderX, position.grid)
+}
+
+func (position *CoordinateConverter) PixelToY(y int32) int32 {
+	return floorDiv(y-position.borderY, position.grid)
+}
+
+// floorDiv rounds toward minus infinity so the pixels left of or above the
+// grid give negative cells.
+func floorDiv(value int32, divisor int32) int32 {
+	quotient := value / divisor
+	if value%divisor != 0 && (value < 0) != (divisor < 0) {
+		quotient--
+	}
+
+	return quotient
+}
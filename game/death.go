@@ -0,0 +1,76 @@
+package game
+
+import "fmt"
+
+type DeathCause int
+
+const (
+	Alive      DeathCause = 0
+	HitWall    DeathCause = 1
+	AteItself  DeathCause = 2
+	TurnedBack DeathCause = 3
+	Exploded   DeathCause = 4
+	Poisoned   DeathCause = 5
+	HitPatrol  DeathCause = 6
+)
+
+type deathCause struct {
+	name    string
+	message string
+}
+
+var deathCauses = map[DeathCause]deathCause{
+	Alive:      {name: "alive", message: ""},
+	HitWall:    {name: "wall", message: "You hit the wall"},
+	AteItself:  {name: "itself", message: "You bit your own body"},
+	TurnedBack: {name: "turned-back", message: "You turned back on yourself"},
+	Exploded:   {name: "bomb", message: "You ate a bomb"},
+	Poisoned:   {name: "poison", message: "The poison was too much for you"},
+	HitPatrol:  {name: "patrol", message: "You ran into a patrol"},
+}
+
+func (cause DeathCause) String() string {
+	return deathCauses[cause].name
+}
+
+func (cause DeathCause) Message() string {
+	return deathCauses[cause].message
+}
+
+func (cause DeathCause) MarshalText() ([]byte, error) {
+	death, ok := deathCauses[cause]
+	if !ok {
+		return nil, fmt.Errorf("unknown death cause %d", cause)
+	}
+
+	return []byte(death.name), nil
+}
+
+func (cause *DeathCause) UnmarshalText(text []byte) error {
+	for value, death := range deathCauses {
+		if death.name == string(text) {
+			*cause = value
+			return nil
+		}
+	}
+
+	return fmt.Errorf("unknown death cause %q", text)
+}
+
+func (board *Board) die(cause DeathCause) {
+	if gameModes[board.mode].immortal {
+		return
+	}
+
+	if board.status != GameOver {
+		board.crash()
+		board.notify(Died)
+	}
+
+	board.deathCause = cause
+	board.status = GameOver
+}
+
+func (board *Board) DeathCause() DeathCause {
+	return board.deathCause
+}
@@ -0,0 +1,164 @@
+package game
+
+import (
+	"fmt"
+	"strconv"
+
+	rl "github.com/gen2brain/raylib-go/raylib"
+)
+
+const debugFontSize = 10
+
+var (
+	debugGrid      = rl.NewColor(255, 0, 255, 90)
+	debugText      = rl.NewColor(255, 0, 255, 255)
+	debugHighlight = rl.NewColor(255, 255, 0, 200)
+	debugPanel     = rl.NewColor(0, 0, 0, 170)
+)
+
+// SetDebug shows the debug overlay over the board: the grid with its
+// coordinates, the ring buffer index of every segment, the cell under the
+// mouse and the counters of the game.
+func (board *Board) SetDebug(enabled bool) {
+	board.debug = enabled
+}
+
+func (board *Board) Debug() bool {
+	return board.debug
+}
+
+func (board *Board) drawDebug() {
+	board.drawDebugGrid()
+	board.drawDebugSegments()
+	board.drawDebugCursor()
+	board.drawDebugPanel()
+}
+
+// drawDebugGrid draws every cell line and numbers the columns in the top
+// border and the rows in the left one, every fifth only when cells are too
+// small for the text.
+func (board *Board) drawDebugGrid() {
+	every := int32(1)
+	if board.cellSize < 2*debugFontSize {
+		every = 5
+	}
+
+	for cell := int32(0); cell <= board.grid; cell++ {
+		x := board.position.XToPixel(cell)
+		y := board.position.YToPixel(cell)
+		rl.DrawLine(x, board.position.YToPixel(0), x, board.position.YToPixel(board.grid), debugGrid)
+		rl.DrawLine(board.position.XToPixel(0), y, board.position.XToPixel(board.grid), y, debugGrid)
+
+		if cell == board.grid || cell%every != 0 {
+			continue
+		}
+
+		label := strconv.Itoa(int(cell))
+		rl.DrawText(label, x+2, board.position.YToPixel(0)-debugFontSize-2, debugFontSize, debugText)
+		rl.DrawText(label, board.position.XToPixel(0)-rl.MeasureText(label, debugFontSize)-2, y+2, debugFontSize, debugText)
+	}
+}
+
+// drawDebugSegments writes on each segment its index in the ring buffer.
+func (board *Board) drawDebugSegments() {
+	snake := board.snake
+	for index := snake.head; index >= snake.tail(); index-- {
+		coord := snake.getBody(index)
+		rl.DrawText(
+			strconv.Itoa(index%len(snake.body)),
+			board.position.XToPixel(coord.x)+1,
+			board.position.YToPixel(coord.y)+1,
+			debugFontSize,
+			debugText,
+		)
+	}
+}
+
+// drawDebugCursor outlines the cell under the mouse and tells its
+// coordinates, those of its top left pixel and what is in it.
+func (board *Board) drawDebugCursor() {
+	mouse := rl.GetMousePosition()
+	x := board.position.PixelToX(int32(mouse.X))
+	y := board.position.PixelToY(int32(mouse.Y))
+	if x < 0 || y < 0 || x >= board.grid || y >= board.grid {
+		return
+	}
+
+	rl.DrawRectangleLines(board.position.XToPixel(x), board.position.YToPixel(y), board.cellSize, board.cellSize, debugHighlight)
+
+	text := fmt.Sprintf("cell %d,%d  pixel %d,%d  %s", x, y, board.position.XToPixel(x), board.position.YToPixel(y), board.cellContent(x, y))
+	rl.DrawText(text, board.border, board.menuSize+board.size-debugFontSize-4, debugFontSize, debugHighlight)
+}
+
+func (board *Board) cellContent(x int32, y int32) string {
+	snake := board.snake
+	for index := snake.head; index >= snake.tail(); index-- {
+		if snake.getBody(index) == newPosition(x, y) {
+			return fmt.Sprintf("segment %d", index%len(snake.body))
+		}
+	}
+
+	for _, food := range board.foods {
+		if food.x == x && food.y == y {
+			return food.kind.String()
+		}
+	}
+
+	if hazard := board.hazardAt(newPosition(x, y)); hazard != nil {
+		return hazard.kind.String()
+	}
+
+	for _, powerUp := range board.powerUps {
+		if powerUp.position == newPosition(x, y) {
+			return powerUp.kind.String()
+		}
+	}
+
+	return "free"
+}
+
+func (board *Board) drawDebugPanel() {
+	snake := board.snake
+	lines := []string{
+		fmt.Sprintf("head %d  length %d  capacity %d  tail %d", snake.head%len(snake.body), snake.length, len(snake.body), snake.tail()%len(snake.body)),
+		fmt.Sprintf("free cells %d  to grow %d", board.freeCellCount(), snake.toGrow),
+		fmt.Sprintf("ticks %d  frames %d  move %d/%d", board.ticks, board.frames, board.moveFrames, board.moveInterval()),
+		fmt.Sprintf("queued %s  heading %s", snake.direction, snake.heading),
+	}
+
+	x := board.border + 4
+	y := board.menuSize + board.border + 4
+	width := int32(0)
+	for _, line := range lines {
+		if measure := rl.MeasureText(line, debugFontSize); measure > width {
+			width = measure
+		}
+	}
+
+	rl.DrawRectangle(x-2, y-2, width+4, int32(len(lines))*(debugFontSize+2)+2, debugPanel)
+	for index, line := range lines {
+		rl.DrawText(line, x, y+int32(index)*(debugFontSize+2), debugFontSize, rl.White)
+	}
+}
+
+// freeCellCount counts the cells of the grid the snake isn't on, in one
+// pass over the body rather than one per cell.
+func (board *Board) freeCellCount() int {
+	taken := make([]bool, board.grid*board.grid)
+	free := len(taken)
+
+	snake := board.snake
+	for index := snake.head; index >= snake.tail(); index-- {
+		coord := snake.getBody(index)
+		if coord.x < 0 || coord.y < 0 || coord.x >= board.grid || coord.y >= board.grid {
+			continue
+		}
+
+		if cell := coord.y*board.grid + coord.x; !taken[cell] {
+			taken[cell] = true
+			free--
+		}
+	}
+
+	return free
+}
@@ -0,0 +1,23 @@
+package game
+
+// Event is something that happened on the board a frontend may react to,
+// with a sound for instance.
+type Event int
+
+const (
+	Ate    Event = 0
+	Turned Event = 1
+	Died   Event = 2
+	Won    Event = 3
+)
+
+// SetListener calls listen on every event of the board, nil stops it.
+func (board *Board) SetListener(listen func(Event)) {
+	board.listen = listen
+}
+
+func (board *Board) notify(event Event) {
+	if board.listen != nil {
+		board.listen(event)
+	}
+}
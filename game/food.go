@@ -0,0 +1,291 @@
+package game
+
+import (
+	"fmt"
+	"math/rand"
+
+	rl "github.com/gen2brain/raylib-go/raylib"
+)
+
+type FoodKind int
+
+const (
+	NormalApple    FoodKind = 0
+	GoldenApple    FoodKind = 1
+	ShrinkingFruit FoodKind = 2
+	SpeedFruit     FoodKind = 3
+)
+
+const (
+	maxFoods        = 3
+	speedFruitTicks = 40
+	speedFruitRatio = 0.5
+	blinkingTicks   = 10
+)
+
+type foodType struct {
+	name     string
+	weight   int
+	lifetime int
+	color    rl.Color
+}
+
+// Lifetimes are counted in snake moves, 0 means the food stays until eaten.
+var foodTypes = map[FoodKind]foodType{
+	NormalApple:    {name: "apple", weight: 10, lifetime: 0, color: rl.NewColor(192, 70, 67, 255)},
+	GoldenApple:    {name: "golden", weight: 2, lifetime: 30, color: rl.NewColor(230, 180, 30, 255)},
+	ShrinkingFruit: {name: "shrinking", weight: 3, lifetime: 50, color: rl.NewColor(128, 60, 160, 255)},
+	SpeedFruit:     {name: "speed", weight: 3, lifetime: 50, color: rl.NewColor(50, 120, 220, 255)},
+}
+
+type Food struct {
+	Apple
+	kind      FoodKind
+	ticksLeft int
+}
+
+func newFood(kind FoodKind, x int32, y int32) Food {
+	return Food{
+		Apple: Apple{
+			x: x,
+			y: y,
+		},
+		kind:      kind,
+		ticksLeft: foodTypes[kind].lifetime,
+	}
+}
+
+func (kind FoodKind) String() string {
+	return foodTypes[kind].name
+}
+
+func (kind FoodKind) MarshalText() ([]byte, error) {
+	food, ok := foodTypes[kind]
+	if !ok {
+		return nil, fmt.Errorf("unknown food %d", kind)
+	}
+
+	return []byte(food.name), nil
+}
+
+func (kind *FoodKind) UnmarshalText(text []byte) error {
+	for value, food := range foodTypes {
+		if food.name == string(text) {
+			*kind = value
+			return nil
+		}
+	}
+
+	return fmt.Errorf("unknown food %q", text)
+}
+
+func randomFoodKind() FoodKind {
+	total := 0
+	for _, food := range foodTypes {
+		total += food.weight
+	}
+
+	pick := rand.Intn(total)
+	for kind := NormalApple; kind <= SpeedFruit; kind++ {
+		pick -= foodTypes[kind].weight
+		if pick < 0 {
+			return kind
+		}
+	}
+
+	return NormalApple
+}
+
+// SpawnFood fills the board up to maxFoods, a normal apple is always kept
+// available so the snake can still grow up to a victory.
+func (board *Board) SpawnFood() {
+	if !board.hasFood(NormalApple) {
+		board.spawnFood(NormalApple)
+	}
+
+	for len(board.foods) < maxFoods {
+		if !board.spawnFood(randomFoodKind()) {
+			return
+		}
+	}
+}
+
+func (board *Board) spawnFood(kind FoodKind) bool {
+	freeCells := board.freeCells()
+	if len(freeCells) == 0 {
+		return false
+	}
+
+	freeCell := freeCells[rand.Intn(len(freeCells))]
+	board.foods = append(board.foods, newFood(kind, freeCell.x, freeCell.y))
+
+	return true
+}
+
+// freeCells returns the cells nothing is on: neither the snake, the food,
+// the power-ups nor the hazards, a patrol taking its whole path.
+func (board *Board) freeCells() []Position {
+	taken := make(map[Position]bool)
+	for _, food := range board.foods {
+		taken[newPosition(food.x, food.y)] = true
+	}
+
+	for _, hazard := range board.hazards {
+		taken[hazard.position] = true
+		for _, position := range hazard.path {
+			taken[position] = true
+		}
+	}
+
+	for _, powerUp := range board.powerUps {
+		taken[powerUp.position] = true
+	}
+
+	var freeCells []Position
+	for _, cell := range board.snake.GetFreeCells() {
+		if position := newPosition(cell[0], cell[1]); !taken[position] {
+			freeCells = append(freeCells, position)
+		}
+	}
+
+	return freeCells
+}
+
+func (board *Board) hasFood(kind FoodKind) bool {
+	for _, food := range board.foods {
+		if food.kind == kind {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (board *Board) hasFoodAt(x int32, y int32) bool {
+	for _, food := range board.foods {
+		if food.x == x && food.y == y {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (board *Board) CheckFood() {
+	for index, food := range board.foods {
+		if !board.snake.AppleEatable(food.Apple) {
+			continue
+		}
+
+		board.foods = append(board.foods[:index], board.foods[index+1:]...)
+		board.eat(food)
+		board.SpawnFood()
+
+		return
+	}
+}
+
+// SetGrowAtTail delays the growth of the snake until what it ate leaves
+// its tail, from the next game.
+func (board *Board) SetGrowAtTail(enabled bool) {
+	board.setGameplay(func(next *gameplay) {
+		next.growAtTail = enabled
+	})
+}
+
+func (board *Board) eat(food Food) {
+	color := foodTypes[food.kind].color
+	if food.kind == NormalApple {
+		color = board.theme.Apple
+	}
+	board.burst(food.x, food.y, color)
+	board.notify(Ate)
+
+	switch food.kind {
+	case NormalApple:
+		board.snake.AppleEated(1)
+		board.applesEaten++
+	case GoldenApple:
+		board.snake.AppleEated(3)
+		board.applesEaten++
+	case ShrinkingFruit:
+		board.snake.Shrink(2)
+	case SpeedFruit:
+		board.speedTicks = speedFruitTicks
+	}
+}
+
+func (board *Board) tickFoods() {
+	foods := board.foods[:0]
+	for _, food := range board.foods {
+		if food.ticksLeft > 0 {
+			food.ticksLeft--
+			if food.ticksLeft == 0 {
+				continue
+			}
+		}
+
+		foods = append(foods, food)
+	}
+
+	board.foods = foods
+	board.SpawnFood()
+
+	if board.speedTicks > 0 {
+		board.speedTicks--
+	}
+}
+
+func (board *Board) drawFoods() {
+	for _, food := range board.foods {
+		if food.ticksLeft > 0 && food.ticksLeft <= blinkingTicks && food.ticksLeft%2 == 0 {
+			continue
+		}
+
+		board.drawFood(food)
+	}
+}
+
+func (board *Board) drawFood(food Food) {
+	x := board.position.XToPixel(food.x)
+	y := board.position.YToPixel(food.y)
+	color := foodTypes[food.kind].color
+	if food.kind == NormalApple {
+		color = board.theme.Apple
+	}
+
+	switch food.kind {
+	case GoldenApple:
+		rl.DrawRectangle(x, y, board.cellSize, board.cellSize, color)
+		rl.DrawRectangle(x+board.cellSize/3, y+board.cellSize/3, board.cellSize/3, board.cellSize/3, rl.NewColor(255, 240, 170, 255))
+	case ShrinkingFruit:
+		rl.DrawRectangle(x+board.cellSize/4, y+board.cellSize/4, board.cellSize/2, board.cellSize/2, color)
+	case SpeedFruit:
+		if board.shapeCues {
+			rl.DrawTriangle(
+				rl.NewVector2(float32(x), float32(y+board.cellSize)),
+				rl.NewVector2(float32(x+board.cellSize), float32(y+board.cellSize)),
+				rl.NewVector2(float32(x+board.cellSize/2), float32(y)),
+				color,
+			)
+			return
+		}
+		rl.DrawCircle(x+board.cellSize/2, y+board.cellSize/2, float32(board.cellSize)/2, color)
+	case NormalApple:
+		if atlas := board.snake.atlas; atlas != nil {
+			atlas.draw(appleSprite, x, y, float32(board.cellSize), 0, color)
+			return
+		}
+
+		if board.shapeCues {
+			radius := float32(board.cellSize) / 2
+			rl.DrawCircle(x+board.cellSize/2, y+board.cellSize/2, radius, color)
+			rl.DrawCircleLines(x+board.cellSize/2, y+board.cellSize/2, radius, board.theme.Text)
+			rl.DrawCircleLines(x+board.cellSize/2, y+board.cellSize/2, radius-1, board.theme.Text)
+			return
+		}
+		rl.DrawRectangle(x, y, board.cellSize, board.cellSize, color)
+	default:
+		rl.DrawRectangle(x, y, board.cellSize, board.cellSize, color)
+	}
+}
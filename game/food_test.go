@@ -0,0 +1,51 @@
+package game
+
+import "testing"
+
+// crowdedBoard is a 4x4 board with the snake, a bomb, a patrol and a power
+// up on it, leaving 9 cells free.
+func crowdedBoard() *Board {
+	position := NewCoordinateConverter(20, 60, 140)
+	snake := NewSnake(4, position)
+	snake.Init()
+	snake.Load([]Cell{{X: 0, Y: 0}}, Right)
+
+	board := NewBoard(snake, 600, 4, 20, 0.2, position)
+	board.hazards = []Hazard{
+		{position: newPosition(3, 3), kind: Bomb, ticksLeft: 10},
+		{
+			position:  newPosition(1, 1),
+			kind:      Patrol,
+			ticksLeft: 10,
+			path:      []Position{newPosition(1, 1), newPosition(1, 2), newPosition(1, 3)},
+		},
+	}
+	board.powerUps = []PowerUp{{position: newPosition(3, 0), kind: Shield, ticksLeft: 10}}
+
+	return board
+}
+
+func TestFoodSpawnsOnFreeCells(t *testing.T) {
+	board := crowdedBoard()
+	taken := map[Position]bool{
+		newPosition(0, 0): true,
+		newPosition(3, 3): true,
+		newPosition(1, 1): true,
+		newPosition(1, 2): true,
+		newPosition(1, 3): true,
+		newPosition(3, 0): true,
+	}
+
+	if free := board.freeCells(); len(free) != 16-len(taken) {
+		t.Fatalf("%d free cells, want %d", len(free), 16-len(taken))
+	}
+
+	for spawn := 0; spawn < 100; spawn++ {
+		board.foods = nil
+		board.spawnFood(NormalApple)
+
+		if food := board.foods[0]; taken[newPosition(food.x, food.y)] {
+			t.Fatalf("food spawned on %d,%d which is taken", food.x, food.y)
+		}
+	}
+}
@@ -0,0 +1,34 @@
+package game
+
+// gameplay holds the settings changing the rules. Like the grid size, they
+// only apply on the next new game so that a game in progress keeps the
+// rules it started with, and right away when no game is running.
+type gameplay struct {
+	difficulty      Difficulty
+	wallMode        WallMode
+	tailCut         bool
+	dropCutSegments bool
+	growAtTail      bool
+}
+
+func (board *Board) setGameplay(change func(next *gameplay)) {
+	change(&board.next)
+
+	if !board.Running() {
+		board.applyGameplay()
+	}
+}
+
+func (board *Board) applyGameplay() {
+	// A custom speed curve is kept until the difficulty changes.
+	if board.curve == nil || board.difficulty != board.next.difficulty {
+		board.difficulty = board.next.difficulty
+		board.curve = difficulties[board.difficulty].curve
+		board.speed = board.curve.Speed(board.snake.Size())
+	}
+
+	board.wallMode = board.next.wallMode
+	board.tailCut = board.next.tailCut
+	board.dropCutSegments = board.next.dropCutSegments
+	board.snake.SetGrowAtTail(board.next.growAtTail)
+}
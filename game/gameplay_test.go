@@ -0,0 +1,45 @@
+package game
+
+import "testing"
+
+func newTestBoard() *Board {
+	position := NewCoordinateConverter(20, 60, 28)
+	snake := NewSnake(20, position)
+	snake.Init()
+
+	return NewBoard(snake, 600, 20, 20, 0.2, position)
+}
+
+func TestGameplayWaitsForTheNextGame(t *testing.T) {
+	board := newTestBoard()
+	board.Start()
+	speed := board.speed
+
+	board.SetDifficulty(Hard)
+	board.SetWallMode(WrappedWalls)
+	board.SetTailCut(true, true)
+
+	if board.difficulty != Normal || board.speed != speed || board.wallMode == WrappedWalls || board.tailCut {
+		t.Fatal("the rules changed during the game")
+	}
+
+	if board.Difficulty() != Hard || board.WallMode() != WrappedWalls {
+		t.Fatal("the getters should tell the next rules")
+	}
+
+	board.Start()
+
+	if board.difficulty != Hard || board.wallMode != WrappedWalls || !board.tailCut || !board.dropCutSegments {
+		t.Fatal("the next game should use the new rules")
+	}
+}
+
+func TestGameplayAppliesWithoutAGame(t *testing.T) {
+	board := newTestBoard()
+
+	board.SetDifficulty(Hard)
+
+	if board.difficulty != Hard || board.speed != difficulties[Hard].curve.Speed(board.snake.Size()) {
+		t.Fatal("the rules should apply right away when no game runs")
+	}
+}
@@ -0,0 +1,241 @@
+package game
+
+import (
+	"fmt"
+	"math/rand"
+
+	rl "github.com/gen2brain/raylib-go/raylib"
+)
+
+type HazardKind int
+
+const (
+	Poison HazardKind = 0
+	Bomb   HazardKind = 1
+	Patrol HazardKind = 2
+)
+
+const (
+	maxHazards         = 3
+	hazardSpawnTicks   = 25
+	hazardSafeDistance = 3
+	poisonSegments     = 3
+	patrolMinLength    = 3
+	patrolMaxLength    = 6
+)
+
+type hazardType struct {
+	name     string
+	lifetime int
+}
+
+var hazardTypes = map[HazardKind]hazardType{
+	Poison: {name: "poison", lifetime: 60},
+	Bomb:   {name: "bomb", lifetime: 80},
+	Patrol: {name: "patrol", lifetime: 120},
+}
+
+type Hazard struct {
+	position  Position
+	kind      HazardKind
+	ticksLeft int
+	path      []Position
+	step      int
+	backward  bool
+}
+
+func (kind HazardKind) String() string {
+	return hazardTypes[kind].name
+}
+
+func (kind HazardKind) MarshalText() ([]byte, error) {
+	hazard, ok := hazardTypes[kind]
+	if !ok {
+		return nil, fmt.Errorf("unknown hazard %d", kind)
+	}
+
+	return []byte(hazard.name), nil
+}
+
+func (kind *HazardKind) UnmarshalText(text []byte) error {
+	for value, hazard := range hazardTypes {
+		if hazard.name == string(text) {
+			*kind = value
+			return nil
+		}
+	}
+
+	return fmt.Errorf("unknown hazard %q", text)
+}
+
+func (board *Board) tickHazards() {
+	hazards := board.hazards[:0]
+	for _, hazard := range board.hazards {
+		hazard.ticksLeft--
+		if hazard.ticksLeft <= 0 {
+			continue
+		}
+
+		hazard.patrol()
+		hazards = append(hazards, hazard)
+	}
+
+	board.hazards = hazards
+	board.hazardTicks++
+
+	if board.hazardTicks%hazardSpawnTicks == 0 && len(board.hazards) < maxHazards {
+		board.spawnHazard(HazardKind(rand.Intn(len(hazardTypes))))
+	}
+}
+
+func (hazard *Hazard) patrol() {
+	if len(hazard.path) < 2 {
+		return
+	}
+
+	if hazard.step == len(hazard.path)-1 {
+		hazard.backward = true
+	}
+
+	if hazard.step == 0 {
+		hazard.backward = false
+	}
+
+	if hazard.backward {
+		hazard.step--
+	} else {
+		hazard.step++
+	}
+
+	hazard.position = hazard.path[hazard.step]
+}
+
+func (board *Board) spawnHazard(kind HazardKind) {
+	freeCells := board.hazardFreeCells()
+	if len(freeCells) == 0 {
+		return
+	}
+
+	start := freeCells[rand.Intn(len(freeCells))]
+	hazard := Hazard{
+		position:  start,
+		kind:      kind,
+		ticksLeft: hazardTypes[kind].lifetime,
+	}
+
+	if kind == Patrol {
+		hazard.path = board.patrolPath(start, freeCells)
+	}
+
+	board.hazards = append(board.hazards, hazard)
+}
+
+// hazardFreeCells returns the free cells a hazard can spawn on, keeping
+// clear of the cells right around the head so nothing appears where the
+// player cannot avoid it.
+func (board *Board) hazardFreeCells() []Position {
+	head := board.snake.getBody(board.snake.head)
+
+	var freeCells []Position
+	for _, position := range board.freeCells() {
+		if abs(position.x-head.x)+abs(position.y-head.y) < hazardSafeDistance {
+			continue
+		}
+
+		freeCells = append(freeCells, position)
+	}
+
+	return freeCells
+}
+
+func (board *Board) patrolPath(start Position, freeCells []Position) []Position {
+	free := make(map[Position]bool, len(freeCells))
+	for _, cell := range freeCells {
+		free[cell] = true
+	}
+
+	stepX, stepY := int32(1), int32(0)
+	if rand.Intn(2) == 0 {
+		stepX, stepY = 0, 1
+	}
+
+	length := patrolMinLength + rand.Intn(patrolMaxLength-patrolMinLength+1)
+	path := []Position{start}
+	for len(path) < length {
+		last := path[len(path)-1]
+		next := newPosition(last.x+stepX, last.y+stepY)
+		if !free[next] {
+			break
+		}
+
+		path = append(path, next)
+	}
+
+	return path
+}
+
+func (board *Board) hazardAt(position Position) *Hazard {
+	for index := range board.hazards {
+		if board.hazards[index].position == position {
+			return &board.hazards[index]
+		}
+	}
+
+	return nil
+}
+
+func (board *Board) CheckHazards() {
+	for index, hazard := range board.hazards {
+		if hazard.kind == Patrol && board.snake.Contains(hazard.position) {
+			board.die(HitPatrol)
+			return
+		}
+
+		if hazard.position != board.snake.getBody(board.snake.head) {
+			continue
+		}
+
+		switch hazard.kind {
+		case Bomb:
+			board.die(Exploded)
+		case Poison:
+			if board.snake.Size() <= poisonSegments {
+				board.die(Poisoned)
+				return
+			}
+
+			board.snake.Shrink(poisonSegments)
+			board.hazards = append(board.hazards[:index], board.hazards[index+1:]...)
+		}
+
+		return
+	}
+}
+
+func (board *Board) drawHazards() {
+	for _, hazard := range board.hazards {
+		x := board.position.XToPixel(hazard.position.x)
+		y := board.position.YToPixel(hazard.position.y)
+		center := float32(board.cellSize) / 2
+
+		switch hazard.kind {
+		case Poison:
+			rl.DrawCircle(x+board.cellSize/2, y+board.cellSize/2, center, rl.NewColor(90, 140, 40, 255))
+			rl.DrawCircle(x+board.cellSize/2, y+board.cellSize/2, center/2, rl.NewColor(30, 50, 10, 255))
+		case Bomb:
+			rl.DrawCircle(x+board.cellSize/2, y+board.cellSize/2, center, rl.Black)
+			rl.DrawCircle(x+board.cellSize/2, y+board.cellSize/2, center/3, rl.Red)
+		case Patrol:
+			rl.DrawRectangle(x, y, board.cellSize, board.cellSize, rl.Orange)
+			rl.DrawRectangleLines(x, y, board.cellSize, board.cellSize, rl.Maroon)
+		}
+	}
+}
+
+func abs(value int32) int32 {
+	if value < 0 {
+		return -value
+	}
+
+	return value
+}
@@ -0,0 +1,28 @@
+package game
+
+import "testing"
+
+func TestHazardsSpawnOnFreeCells(t *testing.T) {
+	board := crowdedBoard()
+	board.foods = []Food{newFood(NormalApple, 2, 3)}
+	hazards := board.hazards
+
+	for _, position := range board.hazardFreeCells() {
+		if position == newPosition(3, 0) || position == newPosition(1, 2) || position == newPosition(2, 3) {
+			t.Fatalf("%v is taken, a hazard can't spawn there", position)
+		}
+
+		if position.x+position.y < hazardSafeDistance {
+			t.Fatalf("%v is too close to the head", position)
+		}
+	}
+
+	for spawn := 0; spawn < 100; spawn++ {
+		board.hazards = hazards
+		board.spawnHazard(Bomb)
+
+		if bomb := board.hazards[len(board.hazards)-1].position; bomb == newPosition(3, 0) {
+			t.Fatal("a bomb spawned on the power-up")
+		}
+	}
+}
@@ -0,0 +1,195 @@
+package game
+
+import (
+	"math"
+	"math/rand"
+
+	rl "github.com/gen2brain/raylib-go/raylib"
+)
+
+// The juice is the eye candy drawn over the board: particles, a shake and
+// a red flash on game over, a glow on the apples being digested and
+// fireworks on victory. It runs on the frame time, not on the moves.
+const (
+	maxParticles      = 256
+	burstParticles    = 24
+	shakeSeconds      = 0.4
+	shakePixels       = 8
+	flashSeconds      = 0.35
+	celebrateSeconds  = 3
+	fireworkSeconds   = 0.25
+	fireworkParticles = 40
+	particleGravity   = 220
+)
+
+type particle struct {
+	position rl.Vector2
+	velocity rl.Vector2
+	life     float32
+	lifetime float32
+	size     float32
+	color    rl.Color
+	gravity  bool
+}
+
+// juice keeps its particles in a fixed pool, a new one takes the place of
+// the oldest so that no frame allocates.
+type juice struct {
+	enabled   bool
+	particles [maxParticles]particle
+	next      int
+	shake     float32
+	flash     float32
+	celebrate float32
+	firework  float32
+	clock     float32
+}
+
+// SetJuice turns the particles and the other effects on or off.
+func (board *Board) SetJuice(enabled bool) {
+	board.juice.enabled = enabled
+	if !enabled {
+		board.juice = juice{}
+	}
+}
+
+func (juice *juice) emit(position rl.Vector2, color rl.Color, speed float32, lifetime float32, gravity bool) {
+	angle := rand.Float64() * 2 * math.Pi
+	velocity := speed * (0.4 + 0.6*rand.Float32())
+
+	juice.particles[juice.next] = particle{
+		position: position,
+		velocity: rl.NewVector2(float32(math.Cos(angle))*velocity, float32(math.Sin(angle))*velocity),
+		life:     lifetime,
+		lifetime: lifetime,
+		size:     2 + 3*rand.Float32(),
+		color:    color,
+		gravity:  gravity,
+	}
+	juice.next = (juice.next + 1) % maxParticles
+}
+
+// burst throws particles out of the cell at x, y, when the snake eats.
+func (board *Board) burst(x int32, y int32, color rl.Color) {
+	if !board.juice.enabled {
+		return
+	}
+
+	center := rl.NewVector2(
+		float32(board.position.XToPixel(x)+board.cellSize/2),
+		float32(board.position.YToPixel(y)+board.cellSize/2),
+	)
+	for count := 0; count < burstParticles; count++ {
+		board.juice.emit(center, color, float32(board.cellSize)*4, 0.6, false)
+	}
+}
+
+// crash shakes the board and flashes it red, when the snake dies.
+func (board *Board) crash() {
+	if !board.juice.enabled {
+		return
+	}
+
+	board.juice.shake = shakeSeconds
+	board.juice.flash = flashSeconds
+}
+
+// celebrate fires fireworks for a while, on victory.
+func (board *Board) celebrate() {
+	if !board.juice.enabled {
+		return
+	}
+
+	board.juice.celebrate = celebrateSeconds
+	board.juice.firework = 0
+}
+
+// updateJuice moves the juice seconds forward.
+func (board *Board) updateJuice(seconds float32) {
+	juice := &board.juice
+	juice.clock += seconds
+	juice.shake = float32(math.Max(0, float64(juice.shake-seconds)))
+	juice.flash = float32(math.Max(0, float64(juice.flash-seconds)))
+
+	if juice.celebrate > 0 {
+		juice.celebrate -= seconds
+		juice.firework -= seconds
+
+		if juice.firework <= 0 {
+			juice.firework = fireworkSeconds
+			center := rl.NewVector2(
+				float32(board.border)+rand.Float32()*float32(board.size-2*board.border),
+				float32(board.menuSize+board.border)+rand.Float32()*float32(board.size-2*board.border)/2,
+			)
+			color := rl.NewColor(uint8(120+rand.Intn(136)), uint8(120+rand.Intn(136)), uint8(120+rand.Intn(136)), 255)
+			for count := 0; count < fireworkParticles; count++ {
+				juice.emit(center, color, 180, 1.2, true)
+			}
+		}
+	}
+
+	for index := range juice.particles {
+		particle := &juice.particles[index]
+		if particle.life <= 0 {
+			continue
+		}
+
+		particle.life -= seconds
+		if particle.gravity {
+			particle.velocity.Y += particleGravity * seconds
+		}
+		particle.position.X += particle.velocity.X * seconds
+		particle.position.Y += particle.velocity.Y * seconds
+	}
+}
+
+// beginShake offsets the drawing while the board shakes, endShake has to
+// follow.
+func (board *Board) beginShake() {
+	strength := board.juice.shake / shakeSeconds * shakePixels
+	offset := rl.NewVector2((rand.Float32()*2-1)*strength, (rand.Float32()*2-1)*strength)
+
+	rl.BeginMode2D(rl.NewCamera2D(offset, rl.NewVector2(0, 0), 0, 1))
+}
+
+func (board *Board) endShake() {
+	rl.EndMode2D()
+}
+
+// drawJuice draws the glow of the apples being digested, the particles and
+// the red flash over the board.
+func (board *Board) drawJuice() {
+	juice := &board.juice
+
+	pulse := float32(0.5 + 0.5*math.Sin(float64(juice.clock)*8))
+	snake := board.snake
+	for index := snake.head; index >= snake.tail(); index-- {
+		if !snake.digesting(index) {
+			continue
+		}
+
+		coord := snake.getBody(index)
+		rl.DrawCircleGradient(
+			board.position.XToPixel(coord.x)+board.cellSize/2,
+			board.position.YToPixel(coord.y)+board.cellSize/2,
+			float32(board.cellSize)*(0.7+0.2*pulse),
+			withAlpha(board.theme.Apple, uint8(90+80*pulse)),
+			withAlpha(board.theme.Apple, 0),
+		)
+	}
+
+	for index := range juice.particles {
+		particle := &juice.particles[index]
+		if particle.life <= 0 {
+			continue
+		}
+
+		alpha := uint8(255 * particle.life / particle.lifetime)
+		rl.DrawCircleV(particle.position, particle.size, withAlpha(particle.color, alpha))
+	}
+
+	if juice.flash > 0 {
+		alpha := uint8(140 * juice.flash / flashSeconds)
+		rl.DrawRectangle(0, board.menuSize, board.size, board.size, rl.NewColor(230, 30, 30, alpha))
+	}
+}
@@ -0,0 +1,203 @@
+package game
+
+import "fmt"
+
+type GameMode int
+
+const (
+	Classic    GameMode = 0
+	TimeAttack GameMode = 1
+	Survival   GameMode = 2
+	Zen        GameMode = 3
+)
+
+// Rules decide how a game mode is won, lost and scored. Moved is called
+// right after each snake move, before food and hazards are checked.
+type Rules interface {
+	Status(board *Board) Status
+	Score(board *Board) int
+	Moved(board *Board)
+	Hud(board *Board) string
+}
+
+type gameMode struct {
+	name     string
+	rules    Rules
+	immortal bool
+}
+
+var gameModes = map[GameMode]gameMode{
+	Classic:    {name: "Classic", rules: classicRules{}},
+	TimeAttack: {name: "Time attack", rules: timeAttackRules{seconds: 60}},
+	Survival:   {name: "Survival", rules: survivalRules{growTicks: 20}},
+	Zen:        {name: "Zen", rules: zenRules{}, immortal: true},
+}
+
+func GameModes() []GameMode {
+	modes := make([]GameMode, 0, len(gameModes))
+	for mode := GameMode(0); int(mode) < len(gameModes); mode++ {
+		modes = append(modes, mode)
+	}
+
+	return modes
+}
+
+// Endless tells whether the mode of the board never ends by itself, its
+// games are over when the player stops them.
+func (board *Board) Endless() bool {
+	return gameModes[board.mode].immortal
+}
+
+func (mode GameMode) String() string {
+	return gameModes[mode].name
+}
+
+func (mode GameMode) Next() GameMode {
+	return (mode + 1) % GameMode(len(gameModes))
+}
+
+func (mode GameMode) Previous() GameMode {
+	return (mode + GameMode(len(gameModes)) - 1) % GameMode(len(gameModes))
+}
+
+func (mode GameMode) MarshalText() ([]byte, error) {
+	gameMode, ok := gameModes[mode]
+	if !ok {
+		return nil, fmt.Errorf("unknown game mode %d", mode)
+	}
+
+	return []byte(gameMode.name), nil
+}
+
+func (mode *GameMode) UnmarshalText(text []byte) error {
+	for value, gameMode := range gameModes {
+		if gameMode.name == string(text) {
+			*mode = value
+			return nil
+		}
+	}
+
+	return fmt.Errorf("unknown game mode %q", text)
+}
+
+func (board *Board) SetMode(mode GameMode) {
+	board.mode = mode
+}
+
+func (board *Board) Mode() GameMode {
+	return board.mode
+}
+
+func (board *Board) rules() Rules {
+	return gameModes[board.mode].rules
+}
+
+type classicRules struct{}
+
+func (rules classicRules) Status(board *Board) Status {
+	if board.snake.Size() == int(board.grid*board.grid)+1 {
+		return Victory
+	}
+
+	return board.deadlyStatus()
+}
+
+func (rules classicRules) Score(board *Board) int {
+	return board.snake.Size() - 1
+}
+
+func (rules classicRules) Moved(board *Board) {
+	if board.snake.IsOutside(0, 0, board.grid, board.grid) {
+		board.hitWall()
+	}
+
+	if board.tailCut && !board.HasEffect(Ghost) {
+		board.cutTail()
+	}
+}
+
+func (rules classicRules) Hud(board *Board) string {
+	return fmt.Sprintf("Score %d", rules.Score(board))
+}
+
+type timeAttackRules struct {
+	seconds int32
+}
+
+func (rules timeAttackRules) Status(board *Board) Status {
+	if rules.secondsLeft(board) <= 0 {
+		return Victory
+	}
+
+	return board.deadlyStatus()
+}
+
+func (rules timeAttackRules) Score(board *Board) int {
+	return board.applesEaten
+}
+
+func (rules timeAttackRules) Moved(board *Board) {
+	classicRules{}.Moved(board)
+}
+
+func (rules timeAttackRules) Hud(board *Board) string {
+	return fmt.Sprintf("Apples %d  %ds", rules.Score(board), rules.secondsLeft(board))
+}
+
+func (rules timeAttackRules) secondsLeft(board *Board) int32 {
+	left := rules.seconds - board.frames/FramesPerSecond
+	if left < 0 {
+		return 0
+	}
+
+	return left
+}
+
+type survivalRules struct {
+	growTicks int
+}
+
+func (rules survivalRules) Status(board *Board) Status {
+	return classicRules{}.Status(board)
+}
+
+func (rules survivalRules) Score(board *Board) int {
+	return board.ticks
+}
+
+func (rules survivalRules) Moved(board *Board) {
+	classicRules{}.Moved(board)
+
+	if board.ticks%rules.growTicks == 0 {
+		board.snake.Grow(1)
+	}
+}
+
+func (rules survivalRules) Hud(board *Board) string {
+	return fmt.Sprintf("Survived %d", rules.Score(board))
+}
+
+// zenRules never end the game: walls wrap and biting the body only cuts the
+// tail off.
+type zenRules struct{}
+
+func (rules zenRules) Status(board *Board) Status {
+	if board.snake.Size() == int(board.grid*board.grid)+1 {
+		return Victory
+	}
+
+	return Continue
+}
+
+func (rules zenRules) Score(board *Board) int {
+	return board.snake.Size() - 1
+}
+
+func (rules zenRules) Moved(board *Board) {
+	board.snake.Wrap()
+	board.cutTail()
+}
+
+func (rules zenRules) Hud(board *Board) string {
+	return fmt.Sprintf("Score %d", rules.Score(board))
+}
@@ -0,0 +1,293 @@
+package game
+
+import (
+	"fmt"
+	"math/rand"
+
+	rl "github.com/gen2brain/raylib-go/raylib"
+)
+
+type PowerUpKind int
+
+const (
+	Ghost      PowerUpKind = 0
+	SlowMotion PowerUpKind = 1
+	Magnet     PowerUpKind = 2
+	Shield     PowerUpKind = 3
+)
+
+type stacking int
+
+const (
+	refresh stacking = 0
+	extend  stacking = 1
+	charges stacking = 2
+)
+
+const (
+	powerUpSpawnTicks = 40
+	powerUpLifetime   = 50
+	slowMotionRatio   = 2
+	magnetRadius      = 5
+	ghostOpacity      = 110
+)
+
+type powerUpType struct {
+	name        string
+	duration    int
+	stacking    stacking
+	maxDuration int
+	maxCharges  int
+	color       rl.Color
+}
+
+// Durations are counted in snake moves. Picking up an effect already running
+// either restarts it (refresh), adds to what is left (extend, up to
+// maxDuration) or adds a charge (charges, up to maxCharges).
+var powerUpTypes = map[PowerUpKind]powerUpType{
+	Ghost:      {name: "ghost", duration: 30, stacking: refresh, color: rl.NewColor(150, 150, 220, 255)},
+	SlowMotion: {name: "slow", duration: 25, stacking: extend, maxDuration: 75, color: rl.NewColor(90, 190, 190, 255)},
+	Magnet:     {name: "magnet", duration: 40, stacking: refresh, color: rl.NewColor(200, 60, 140, 255)},
+	Shield:     {name: "shield", duration: 100, stacking: charges, maxCharges: 3, color: rl.NewColor(0, 121, 241, 255)},
+}
+
+type PowerUp struct {
+	position  Position
+	kind      PowerUpKind
+	ticksLeft int
+}
+
+type Effect struct {
+	kind      PowerUpKind
+	ticksLeft int
+	charges   int
+}
+
+func (kind PowerUpKind) String() string {
+	return powerUpTypes[kind].name
+}
+
+func (kind PowerUpKind) MarshalText() ([]byte, error) {
+	powerUp, ok := powerUpTypes[kind]
+	if !ok {
+		return nil, fmt.Errorf("unknown power-up %d", kind)
+	}
+
+	return []byte(powerUp.name), nil
+}
+
+func (kind *PowerUpKind) UnmarshalText(text []byte) error {
+	for value, powerUp := range powerUpTypes {
+		if powerUp.name == string(text) {
+			*kind = value
+			return nil
+		}
+	}
+
+	return fmt.Errorf("unknown power-up %q", text)
+}
+
+func (board *Board) tickPowerUps() {
+	powerUps := board.powerUps[:0]
+	for _, powerUp := range board.powerUps {
+		powerUp.ticksLeft--
+		if powerUp.ticksLeft > 0 {
+			powerUps = append(powerUps, powerUp)
+		}
+	}
+	board.powerUps = powerUps
+
+	effects := board.effects[:0]
+	for _, effect := range board.effects {
+		effect.ticksLeft--
+		if effect.ticksLeft > 0 {
+			effects = append(effects, effect)
+		}
+	}
+	board.effects = effects
+
+	if board.HasEffect(Magnet) {
+		board.pullApples()
+	}
+
+	board.powerUpTicks++
+	if board.powerUpTicks%powerUpSpawnTicks == 0 && len(board.powerUps) == 0 {
+		board.spawnPowerUp(PowerUpKind(rand.Intn(len(powerUpTypes))))
+	}
+}
+
+func (board *Board) spawnPowerUp(kind PowerUpKind) {
+	freeCells := board.hazardFreeCells()
+	if len(freeCells) == 0 {
+		return
+	}
+
+	board.powerUps = append(board.powerUps, PowerUp{
+		position:  freeCells[rand.Intn(len(freeCells))],
+		kind:      kind,
+		ticksLeft: powerUpLifetime,
+	})
+}
+
+func (board *Board) CheckPowerUps() {
+	head := board.snake.getBody(board.snake.head)
+
+	for index, powerUp := range board.powerUps {
+		if powerUp.position != head {
+			continue
+		}
+
+		board.powerUps = append(board.powerUps[:index], board.powerUps[index+1:]...)
+		board.activate(powerUp.kind)
+
+		return
+	}
+}
+
+func (board *Board) activate(kind PowerUpKind) {
+	powerUp := powerUpTypes[kind]
+
+	effect := board.effect(kind)
+	if effect == nil {
+		board.effects = append(board.effects, Effect{
+			kind:      kind,
+			ticksLeft: powerUp.duration,
+			charges:   1,
+		})
+		return
+	}
+
+	switch powerUp.stacking {
+	case refresh:
+		effect.ticksLeft = powerUp.duration
+	case extend:
+		effect.ticksLeft += powerUp.duration
+		if effect.ticksLeft > powerUp.maxDuration {
+			effect.ticksLeft = powerUp.maxDuration
+		}
+	case charges:
+		effect.ticksLeft = powerUp.duration
+		if effect.charges < powerUp.maxCharges {
+			effect.charges++
+		}
+	}
+}
+
+func (board *Board) effect(kind PowerUpKind) *Effect {
+	for index := range board.effects {
+		if board.effects[index].kind == kind {
+			return &board.effects[index]
+		}
+	}
+
+	return nil
+}
+
+func (board *Board) HasEffect(kind PowerUpKind) bool {
+	return board.effect(kind) != nil
+}
+
+// useShield spends one shield charge, the snake then comes back from the
+// opposite side of the board instead of hitting the wall.
+func (board *Board) useShield() bool {
+	effect := board.effect(Shield)
+	if effect == nil {
+		return false
+	}
+
+	effect.charges--
+	if effect.charges == 0 {
+		board.removeEffect(Shield)
+	}
+
+	board.snake.Wrap()
+
+	return true
+}
+
+func (board *Board) removeEffect(kind PowerUpKind) {
+	for index := range board.effects {
+		if board.effects[index].kind == kind {
+			board.effects = append(board.effects[:index], board.effects[index+1:]...)
+			return
+		}
+	}
+}
+
+func (board *Board) pullApples() {
+	head := board.snake.getBody(board.snake.head)
+
+	for index := range board.foods {
+		food := &board.foods[index]
+		if food.kind != NormalApple || abs(food.x-head.x)+abs(food.y-head.y) > magnetRadius {
+			continue
+		}
+
+		next := newPosition(food.x+sign(head.x-food.x), food.y)
+		if food.x == head.x {
+			next = newPosition(food.x, food.y+sign(head.y-food.y))
+		}
+
+		if next == head || !board.snake.Contains(next) && !board.hasFoodAt(next.x, next.y) && board.hazardAt(next) == nil {
+			food.x = next.x
+			food.y = next.y
+		}
+	}
+}
+
+func (board *Board) drawPowerUps() {
+	for _, powerUp := range board.powerUps {
+		x := board.position.XToPixel(powerUp.position.x)
+		y := board.position.YToPixel(powerUp.position.y)
+		color := powerUpTypes[powerUp.kind].color
+
+		rl.DrawRectangleLines(x, y, board.cellSize, board.cellSize, color)
+		rl.DrawRectangle(x+board.cellSize/4, y+board.cellSize/4, board.cellSize/2, board.cellSize/2, color)
+	}
+}
+
+func (board *Board) drawEffects() {
+	x := int32(100)
+
+	for _, effect := range board.effects {
+		powerUp := powerUpTypes[effect.kind]
+		label := fmt.Sprintf("%s %d", powerUp.name, effect.ticksLeft)
+		if powerUp.stacking == charges {
+			label = fmt.Sprintf("%s x%d", powerUp.name, effect.charges)
+		}
+
+		rl.DrawRectangle(x, 12, 16, 16, powerUp.color)
+		rl.DrawText(label, x+20, 12, 16, board.theme.Text)
+		x += 30 + rl.MeasureText(label, 16)
+	}
+}
+
+func (board *Board) drawShield() {
+	if !board.HasEffect(Shield) {
+		return
+	}
+
+	head := board.snake.getBody(board.snake.head)
+	rl.DrawRectangleLinesEx(
+		rl.NewRectangle(
+			float32(board.position.XToPixel(head.x))-2,
+			float32(board.position.YToPixel(head.y))-2,
+			float32(board.cellSize)+4,
+			float32(board.cellSize)+4,
+		),
+		2,
+		powerUpTypes[Shield].color,
+	)
+}
+
+func sign(value int32) int32 {
+	if value < 0 {
+		return -1
+	}
+
+	if value > 0 {
+		return 1
+	}
+
+	return 0
+}
@@ -0,0 +1,111 @@
+package game
+
+import rl "github.com/gen2brain/raylib-go/raylib"
+
+// SetSmooth draws the snake gliding between the cells instead of jumping
+// from one to the next, the rules stay on the grid.
+func (board *Board) SetSmooth(enabled bool) {
+	board.smooth = enabled
+}
+
+// moveFraction is how far the snake went toward its next move, from 0 right
+// after a move to 1.
+func (board *Board) moveFraction() float32 {
+	if board.status != Continue && board.status != Pause {
+		return 1
+	}
+
+	fraction := float32(board.moveFrames) / float32(board.moveInterval())
+	if fraction > 1 {
+		return 1
+	}
+
+	return fraction
+}
+
+// center returns the pixel at the center of the cell of position.
+func (snake *Snake) center(position Position, size int32) rl.Vector2 {
+	return rl.NewVector2(
+		float32(snake.coordinateConverter.XToPixel(position.x))+float32(size)/2,
+		float32(snake.coordinateConverter.YToPixel(position.y))+float32(size)/2,
+	)
+}
+
+// glide returns the point fraction of the way from the cell the head left
+// to the one it is in, staying in place across a wrapping wall.
+func (snake *Snake) glide(from Position, to Position, size int32, fraction float32) rl.Vector2 {
+	end := snake.center(to, size)
+	if abs(to.x-from.x)+abs(to.y-from.y) != 1 {
+		return end
+	}
+
+	start := snake.center(from, size)
+
+	return rl.NewVector2(start.X+(end.X-start.X)*fraction, start.Y+(end.Y-start.Y)*fraction)
+}
+
+// drawSmooth draws the snake as a continuous rounded body, the head and the
+// tail gliding the fraction of the move elapsed. It lags a move behind the
+// rules so the head never shows in a cell it has not reached yet.
+func (snake *Snake) drawSmooth(size int32, fraction float32) {
+	if !snake.hasMoved {
+		fraction = 1
+	}
+
+	degradedStep := snake.degradedStep()
+	radius := float32(size) * 0.45
+
+	points := make([]rl.Vector2, 0, snake.length+1)
+	for index := snake.head; index >= snake.tail(); index-- {
+		coord := snake.getBody(index)
+
+		if index == snake.head && fraction < 1 {
+			points = append(points, snake.glide(snake.getBody(index-1), coord, size, fraction))
+			continue
+		}
+
+		points = append(points, snake.center(coord, size))
+	}
+
+	// The tail leaves the cell it was in, unless the snake grew instead.
+	if fraction < 1 && !snake.grew {
+		tail := snake.getBody(snake.tail())
+		left := snake.getBody(snake.tail() - 1)
+		if abs(tail.x-left.x)+abs(tail.y-left.y) == 1 {
+			start := snake.center(left, size)
+			end := snake.center(tail, size)
+			points = append(points, rl.NewVector2(start.X+(end.X-start.X)*fraction, start.Y+(end.Y-start.Y)*fraction))
+		}
+	}
+
+	if len(points) == 0 {
+		return
+	}
+
+	for index := len(points) - 1; index >= 0; index-- {
+		color := fade(snake.theme.SnakeHead, snake.theme.SnakeTail, float64(degradedStep*index)/140, snake.opacity)
+
+		if index > 0 && adjacent(points[index], points[index-1], float32(size)) {
+			rl.DrawLineEx(points[index], points[index-1], radius*2, color)
+		}
+
+		rl.DrawCircleV(points[index], radius, color)
+	}
+
+	for index := snake.head - 1; index >= snake.tail(); index-- {
+		if snake.digesting(index) {
+			rl.DrawCircleV(snake.center(snake.getBody(index), size), radius*1.25, withAlpha(snake.theme.SnakeTail, snake.opacity))
+		}
+	}
+
+	rl.DrawCircleV(points[0], radius*1.1, withAlpha(snake.theme.SnakeHead, snake.opacity))
+}
+
+// adjacent tells whether two points of the body are at most a cell apart,
+// they aren't across a wrapping wall.
+func adjacent(from rl.Vector2, to rl.Vector2, size float32) bool {
+	dx := from.X - to.X
+	dy := from.Y - to.Y
+
+	return dx*dx+dy*dy <= size*size*1.01
+}
@@ -16,6 +16,16 @@ const (
 	Down  Direction = 3
 )
 
+var clockwise = map[Direction]Direction{Up: Right, Right: Down, Down: Left, Left: Up}
+
+func (direction Direction) Clockwise() Direction {
+	return clockwise[direction]
+}
+
+func (direction Direction) CounterClockwise() Direction {
+	return opposites[clockwise[direction]]
+}
+
 func newPosition(x int32, y int32) Position {
 	return Position{
 		x,
@@ -34,21 +44,40 @@ func NewSnake(grid int32, coordinateConverter CoordinateConverter) *Snake {
 		head:                0,
 		length:              0,
 		body:                make([]Position, grid*grid+1),
+		digestion:           make([]bulge, grid*grid+1),
 		direction:           Up,
 		coordinateConverter: coordinateConverter,
-		needToGrow:          false,
+		toGrow:              0,
+		opacity:             255,
+		theme:               ClassicTheme,
 	}
 }
 
+// bulge is an apple being digested, it stays on its segment until the
+// segment leaves the tail. The growth is owed when the snake did not grow
+// yet.
+type bulge struct {
+	growth int
+	owed   bool
+}
+
 type Snake struct {
 	grid                int32
 	head                int
 	length              int
 	body                []Position
+	theme               Theme
+	shapeCues           bool
+	atlas               *Atlas
+	hasMoved            bool
+	grew                bool
 	direction           Direction
+	heading             Direction
 	coordinateConverter CoordinateConverter
-	needToGrow          bool
-	applesEated         []Apple
+	toGrow              int
+	opacity             uint8
+	digestion           []bulge
+	growAtTail          bool
 }
 
 func (snake *Snake) Init() {
@@ -57,9 +86,14 @@ func (snake *Snake) Init() {
 	startX := rand.Int31n(snake.grid)
 	startY := rand.Int31n(snake.grid)
 
-	snake.setBody(snake.head, newPosition(startX, startY))
 	snake.head = 0
 	snake.length = 1
+	snake.toGrow = 0
+	snake.hasMoved = false
+	for index := range snake.digestion {
+		snake.digestion[index] = bulge{}
+	}
+	snake.setBody(snake.head, newPosition(startX, startY))
 
 	if startX <= int32(math.Floor(float64(snake.grid)*0.33)) && startY <= int32(math.Floor(float64(snake.grid)*0.33)) { // Left Top
 		switch rand.Intn(2) {
@@ -126,6 +160,18 @@ func (snake *Snake) Init() {
 			snake.direction = Left
 		}
 	}
+
+	snake.heading = snake.direction
+}
+
+func (snake *Snake) resize(grid int32, coordinateConverter CoordinateConverter) {
+	snake.grid = grid
+	snake.body = make([]Position, grid*grid+1)
+	snake.digestion = make([]bulge, grid*grid+1)
+	snake.coordinateConverter = coordinateConverter
+	snake.head = 0
+	snake.length = 0
+	snake.hasMoved = false
 }
 
 func (snake *Snake) Size() int {
@@ -160,18 +206,129 @@ func (snake *Snake) AppleEatable(apple Apple) bool {
 	return false
 }
 
-func (snake *Snake) AppleEated(apple Apple) {
-	snake.applesEated = append(snake.applesEated, apple)
-	snake.needToGrow = true
+// AppleEated puts a bulge on the head which travels down the body with its
+// segment. The snake grows right away, or once the bulge leaves the tail
+// when it grows at the tail.
+func (snake *Snake) AppleEated(growth int) {
+	snake.digestion[snake.head%len(snake.digestion)] = bulge{growth: growth, owed: snake.growAtTail}
+
+	if !snake.growAtTail {
+		snake.toGrow += growth
+	}
+}
+
+// digesting tells whether the segment at index carries a bulge.
+func (snake *Snake) digesting(index int) bool {
+	return snake.digestion[index%len(snake.digestion)].growth > 0
+}
+
+// digested drops the bulge of the segment at index, giving the growth it
+// still owes.
+func (snake *Snake) digested(index int) {
+	digestion := &snake.digestion[index%len(snake.digestion)]
+	if digestion.owed {
+		snake.toGrow += digestion.growth
+	}
+
+	*digestion = bulge{}
+}
+
+// Shrink drops count segments from the tail, the head never moves in the
+// ring buffer so only length changes. The removed cells are returned from the
+// former tail towards the head.
+func (snake *Snake) Shrink(count int) []Position {
+	if count > snake.length-1 {
+		count = snake.length - 1
+	}
+
+	removed := make([]Position, 0, count)
+	for index := 0; index < count; index++ {
+		removed = append(removed, snake.getBody(snake.tail()+index))
+		snake.digestion[(snake.tail()+index)%len(snake.digestion)] = bulge{}
+	}
+
+	snake.length -= count
+
+	return removed
+}
+
+// Wrap brings a head that left the grid back from the opposite side.
+func (snake *Snake) Wrap() {
+	head := snake.getBody(snake.head)
+
+	snake.setBody(snake.head, newPosition(
+		(head.x+snake.grid)%snake.grid,
+		(head.y+snake.grid)%snake.grid,
+	))
+}
+
+// SetGrowAtTail makes the snake grow when the bulge of an apple leaves its
+// tail instead of right when it eats, the apples still in the body aren't
+// in the score yet.
+func (snake *Snake) SetGrowAtTail(enabled bool) {
+	snake.growAtTail = enabled
+}
+
+func (snake *Snake) SetOpacity(opacity uint8) {
+	snake.opacity = opacity
+}
+
+func (snake *Snake) Grow(count int) {
+	snake.toGrow += count
+}
+
+// Bite returns the ring buffer index of the body segment the head is on.
+func (snake *Snake) Bite() (int, bool) {
+	head := snake.getBody(snake.head)
+
+	for index := snake.head - 1; index >= snake.tail(); index-- {
+		if snake.getBody(index) == head {
+			return index, true
+		}
+	}
+
+	return 0, false
+}
+
+// CutAt drops the segment at index and all the ones behind it.
+func (snake *Snake) CutAt(index int) []Position {
+	return snake.Shrink(index - snake.tail() + 1)
+}
+
+func (snake *Snake) Tail() Position {
+	return snake.getBody(snake.tail())
+}
+
+func (snake *Snake) tail() int {
+	return snake.head - (snake.length - 1)
+}
+
+func (snake *Snake) Contains(position Position) bool {
+	for index := snake.head; index >= snake.tail(); index-- {
+		if snake.getBody(index) == position {
+			return true
+		}
+	}
+
+	return false
 }
 
 func (snake *Snake) Move() bool {
-	if snake.needToGrow {
-		snake.needToGrow = false
+	// The bulge on the tail is over whether the tail leaves its cell or
+	// stays while the snake grows.
+	snake.digested(snake.tail())
+
+	snake.grew = snake.toGrow > 0
+	snake.hasMoved = true
+
+	if snake.toGrow > 0 {
+		snake.toGrow--
 		snake.length++
 	}
 
 	snake.head++
+	snake.heading = snake.direction
+	snake.digestion[snake.head%len(snake.digestion)] = bulge{}
 
 	snake.setBody(snake.head, newPosition(
 		snake.getBody(snake.head-1).x,
@@ -224,6 +381,17 @@ func (snake *Snake) IsEatingItSelf() bool {
 	return false
 }
 
+// Steer turns a quarter from the direction of the last move, steering
+// twice before the snake moves keeps the last one so it can't turn back.
+func (snake *Snake) Steer(clockwise bool) {
+	if clockwise {
+		snake.direction = snake.heading.Clockwise()
+		return
+	}
+
+	snake.direction = snake.heading.CounterClockwise()
+}
+
 func (snake *Snake) GoingToDirection(direction Direction) bool {
 	switch snake.direction {
 	case Up:
@@ -249,18 +417,28 @@ func (snake *Snake) GoingToDirection(direction Direction) bool {
 	return true
 }
 
+// degradedStep is how far the color moves along the gradient from one
+// segment to the next, a snake without segments has none.
+func (snake *Snake) degradedStep() int {
+	if snake.length == 0 {
+		return 0
+	}
+
+	return 140 / snake.length
+}
+
 func (snake *Snake) Draw(size int32) {
-	applesToDigest := make([]bool, len(snake.applesEated))
-	degradedStep := 140 / snake.length
+	if snake.atlas != nil {
+		snake.drawSprites(size)
+		return
+	}
+
+	degradedStep := snake.degradedStep()
 
 	for index := snake.head; index >= snake.head-(snake.length-1); index-- {
 		coord := snake.getBody(index)
-		bodyColor := []rl.Color{
-			rl.NewColor(157, 196, 98, 255),
-			rl.NewColor(157, 196, 98, 255),
-			rl.NewColor(157, 196, 98, 255),
-			rl.NewColor(157, 196, 98, 255),
-		}
+		tailColor := withAlpha(snake.theme.SnakeTail, snake.opacity)
+		bodyColor := []rl.Color{tailColor, tailColor, tailColor, tailColor}
 
 		if index < snake.head {
 			coordNext := snake.getBody(index + 1)
@@ -282,29 +460,24 @@ func (snake *Snake) Draw(size int32) {
 			}
 		}
 
-		for appleIndex, appleEated := range snake.applesEated {
-			if coord.x == appleEated.x && coord.y == appleEated.y {
-				coordNext := snake.getBody(index + 1)
-				indexShifted := int(math.Max(float64(snake.head-(snake.length-1)), float64(index-snake.length*1/3)))
-
-				if coordNext.x > coord.x { // left
-					bodyColor = snake.generateColor(degradedStep, indexShifted, []int{0, 1, 2, 3})
-				}
+		if index < snake.head && snake.digesting(index) {
+			coordNext := snake.getBody(index + 1)
+			indexShifted := int(math.Max(float64(snake.tail()), float64(index-snake.length*1/3)))
 
-				if coordNext.y > coord.y { // bottom
-					bodyColor = snake.generateColor(degradedStep, indexShifted, []int{0, 3, 1, 2})
-				}
+			if coordNext.x > coord.x { // left
+				bodyColor = snake.generateColor(degradedStep, indexShifted, []int{0, 1, 2, 3})
+			}
 
-				if coordNext.x < coord.x { // right
-					bodyColor = snake.generateColor(degradedStep, indexShifted, []int{2, 3, 0, 1})
-				}
+			if coordNext.y > coord.y { // bottom
+				bodyColor = snake.generateColor(degradedStep, indexShifted, []int{0, 3, 1, 2})
+			}
 
-				if coordNext.y < coord.y { // top
-					bodyColor = snake.generateColor(degradedStep, indexShifted, []int{1, 2, 0, 3})
-				}
+			if coordNext.x < coord.x { // right
+				bodyColor = snake.generateColor(degradedStep, indexShifted, []int{2, 3, 0, 1})
+			}
 
-				applesToDigest[appleIndex] = true
-				break
+			if coordNext.y < coord.y { // top
+				bodyColor = snake.generateColor(degradedStep, indexShifted, []int{1, 2, 0, 3})
 			}
 		}
 
@@ -322,34 +495,30 @@ func (snake *Snake) Draw(size int32) {
 		)
 
 		if index == snake.head {
-			color := rl.NewColor(
-				uint8(47+degradedStep),
-				uint8(78+degradedStep),
-				uint8(0+degradedStep),
-				255,
-			)
+			color := fade(snake.theme.SnakeHead, snake.theme.SnakeTail, float64(degradedStep)/140, snake.opacity)
+			headColor := withAlpha(snake.theme.SnakeHead, snake.opacity)
 
 			colors := make([]rl.Color, 4)
 
 			switch snake.direction {
 			case Up:
 				colors = []rl.Color{
-					rl.NewColor(47, 78, 0, 255),
+					headColor,
 					color,
 					color,
-					rl.NewColor(47, 78, 0, 255),
+					headColor,
 				}
 			case Down:
 				colors = []rl.Color{
 					color,
-					rl.NewColor(47, 78, 0, 255),
-					rl.NewColor(47, 78, 0, 255),
+					headColor,
+					headColor,
 					color,
 				}
 			case Left:
 				colors = []rl.Color{
-					rl.NewColor(47, 78, 0, 255),
-					rl.NewColor(47, 78, 0, 255),
+					headColor,
+					headColor,
 					color,
 					color,
 				}
@@ -357,8 +526,8 @@ func (snake *Snake) Draw(size int32) {
 				colors = []rl.Color{
 					color,
 					color,
-					rl.NewColor(47, 78, 0, 255),
-					rl.NewColor(47, 78, 0, 255),
+					headColor,
+					headColor,
 				}
 			}
 
@@ -374,13 +543,35 @@ func (snake *Snake) Draw(size int32) {
 				colors[2],
 				colors[3],
 			)
+
+			if snake.shapeCues {
+				snake.drawEyes(coord, size)
+			}
 		}
 	}
+}
 
-	for index := range snake.applesEated {
-		if applesToDigest[index] == false {
-			snake.applesEated = append(snake.applesEated[:index], snake.applesEated[index+1:]...)
-		}
+// drawEyes draws two eyes on the head looking where the snake goes.
+func (snake *Snake) drawEyes(coord Position, size int32) {
+	x := float32(snake.coordinateConverter.XToPixel(coord.x))
+	y := float32(snake.coordinateConverter.YToPixel(coord.y))
+	cell := float32(size)
+	look := steps[snake.direction]
+
+	// The eyes sit on the front half of the head, side by side across the
+	// direction.
+	forward := rl.NewVector2(float32(look.x), float32(look.y))
+	across := rl.NewVector2(-forward.Y, forward.X)
+
+	for _, side := range []float32{-1, 1} {
+		center := rl.NewVector2(
+			x+cell/2+forward.X*cell/5+across.X*side*cell/4,
+			y+cell/2+forward.Y*cell/5+across.Y*side*cell/4,
+		)
+		pupil := rl.NewVector2(center.X+forward.X*cell/12, center.Y+forward.Y*cell/12)
+
+		rl.DrawCircleV(center, cell/6, withAlpha(rl.White, snake.opacity))
+		rl.DrawCircleV(pupil, cell/11, withAlpha(rl.Black, snake.opacity))
 	}
 }
 
@@ -395,33 +586,13 @@ func (snake *Snake) getBody(index int) Position {
 func (snake *Snake) generateColor(degradedStep int, index int, colorOrders []int) []rl.Color {
 	color := make([]rl.Color, 4)
 
-	color[colorOrders[0]] = rl.NewColor(
-		uint8(47+math.Min(140, float64(degradedStep*(snake.head-index+1)))),
-		uint8(78+math.Min(140, float64(degradedStep*(snake.head-index+1)))),
-		uint8(0+math.Min(140, float64(degradedStep*(snake.head-index+1)))),
-		255,
-	)
-
-	color[colorOrders[1]] = rl.NewColor(
-		uint8(47+math.Min(140, float64(degradedStep*(snake.head-index+1)))),
-		uint8(78+math.Min(140, float64(degradedStep*(snake.head-index+1)))),
-		uint8(0+math.Min(140, float64(degradedStep*(snake.head-index+1)))),
-		255,
-	)
-
-	color[colorOrders[2]] = rl.NewColor(
-		uint8(47+math.Min(140, float64(degradedStep*(snake.head-index)))),
-		uint8(78+math.Min(140, float64(degradedStep*(snake.head-index)))),
-		uint8(0+math.Min(140, float64(degradedStep*(snake.head-index)))),
-		255,
-	)
-
-	color[colorOrders[3]] = rl.NewColor(
-		uint8(47+math.Min(140, float64(degradedStep*(snake.head-index)))),
-		uint8(78+math.Min(140, float64(degradedStep*(snake.head-index)))),
-		uint8(0+math.Min(140, float64(degradedStep*(snake.head-index)))),
-		255,
-	)
+	front := fade(snake.theme.SnakeHead, snake.theme.SnakeTail, float64(degradedStep*(snake.head-index+1))/140, snake.opacity)
+	back := fade(snake.theme.SnakeHead, snake.theme.SnakeTail, float64(degradedStep*(snake.head-index))/140, snake.opacity)
+
+	color[colorOrders[0]] = front
+	color[colorOrders[1]] = front
+	color[colorOrders[2]] = back
+	color[colorOrders[3]] = back
 
 	return color
 }
@@ -0,0 +1,116 @@
+package game
+
+import (
+	"reflect"
+	"testing"
+)
+
+// wrappedSnake moves a snake of 6 segments on a 3x3 grid, a ring buffer of
+// 10, until its head is at index 12 and its tail at index 7: the indices
+// went past the length of the buffer. The snake is at y 0 from x 7 to 12.
+func wrappedSnake(t *testing.T) *Snake {
+	snake := NewSnake(3, NewCoordinateConverter(0, 0, 10))
+	snake.Load([]Cell{{X: 0, Y: 0}}, Right)
+	snake.Grow(5)
+
+	for move := 0; move < 12; move++ {
+		snake.Move()
+	}
+
+	if snake.head != 12 || snake.tail() != 7 || len(snake.body) != 10 {
+		t.Fatalf("head %d, tail %d, buffer %d", snake.head, snake.tail(), len(snake.body))
+	}
+
+	return snake
+}
+
+// turnBack turns the snake down, left and up so that its head lands on its
+// own body at 11,0.
+func turnBack(snake *Snake) {
+	for _, direction := range []Direction{Down, Left, Up} {
+		snake.GoingToDirection(direction)
+		snake.Move()
+	}
+}
+
+func TestWrappedTail(t *testing.T) {
+	snake := wrappedSnake(t)
+
+	if tail := snake.Tail(); tail != newPosition(7, 0) {
+		t.Fatalf("tail = %v, want 7,0", tail)
+	}
+
+	for x := int32(7); x <= 12; x++ {
+		if !snake.Contains(newPosition(x, 0)) {
+			t.Fatalf("snake does not contain %d,0", x)
+		}
+	}
+
+	if snake.Contains(newPosition(6, 0)) {
+		t.Fatal("snake contains the cell its tail left")
+	}
+}
+
+func TestBiteAcrossWrap(t *testing.T) {
+	snake := wrappedSnake(t)
+	turnBack(snake)
+
+	// The head is at index 15, slot 5, and the tail at index 10, slot 0.
+	if snake.head != 15 || snake.tail() != 10 {
+		t.Fatalf("head %d, tail %d", snake.head, snake.tail())
+	}
+
+	index, bitten := snake.Bite()
+	if !bitten || index != 11 {
+		t.Fatalf("Bite() = %d, %v, want 11, true", index, bitten)
+	}
+
+	if !snake.IsEatingItSelf() {
+		t.Fatal("the snake should be eating itself")
+	}
+}
+
+func TestCutAtAcrossWrap(t *testing.T) {
+	snake := wrappedSnake(t)
+	turnBack(snake)
+
+	index, _ := snake.Bite()
+	removed := snake.CutAt(index)
+
+	if want := []Position{{10, 0}, {11, 0}}; !reflect.DeepEqual(removed, want) {
+		t.Fatalf("CutAt removed %v, want %v", removed, want)
+	}
+
+	if snake.Size() != 4 || snake.Tail() != newPosition(12, 0) {
+		t.Fatalf("size %d, tail %v, want 4 and 12,0", snake.Size(), snake.Tail())
+	}
+
+	if snake.IsEatingItSelf() {
+		t.Fatal("the bitten segment should be gone")
+	}
+}
+
+func TestShrinkAcrossWrap(t *testing.T) {
+	snake := wrappedSnake(t)
+	turnBack(snake)
+
+	removed := snake.Shrink(10)
+
+	want := []Position{{10, 0}, {11, 0}, {12, 0}, {12, 1}, {11, 1}}
+	if !reflect.DeepEqual(removed, want) {
+		t.Fatalf("Shrink removed %v, want %v", removed, want)
+	}
+
+	if snake.Size() != 1 || snake.Tail() != newPosition(11, 0) {
+		t.Fatalf("size %d, tail %v, want the head alone", snake.Size(), snake.Tail())
+	}
+}
+
+func TestCellsAcrossWrap(t *testing.T) {
+	snake := wrappedSnake(t)
+
+	want := []Cell{{12, 0}, {11, 0}, {10, 0}, {9, 0}, {8, 0}, {7, 0}}
+	if cells := snake.Cells(); !reflect.DeepEqual(cells, want) {
+		t.Fatalf("Cells() = %v, want %v", cells, want)
+	}
+}
@@ -0,0 +1,138 @@
+package game
+
+import (
+	"fmt"
+	"math"
+)
+
+// SpeedCurve gives the board speed for a snake size, the speed being the
+// time between two moves so lower is faster.
+type SpeedCurve interface {
+	Speed(size int) float32
+}
+
+type SpeedStep struct {
+	Size  int
+	Speed float32
+}
+
+// SpeedTable uses the speed of the last step reached by the snake, steps are
+// sorted by growing size.
+type SpeedTable []SpeedStep
+
+func (table SpeedTable) Speed(size int) float32 {
+	speed := table[0].Speed
+
+	for _, step := range table {
+		if size < step.Size {
+			break
+		}
+
+		speed = step.Speed
+	}
+
+	return speed
+}
+
+// SpeedFormula starts at Start and gets faster by Step for each segment,
+// never going below Min.
+type SpeedFormula struct {
+	Start float32
+	Step  float32
+	Min   float32
+}
+
+func (formula SpeedFormula) Speed(size int) float32 {
+	return float32(math.Max(float64(formula.Min), float64(formula.Start-formula.Step*float32(size-1))))
+}
+
+type Difficulty int
+
+const (
+	Easy   Difficulty = 0
+	Normal Difficulty = 1
+	Hard   Difficulty = 2
+	Insane Difficulty = 3
+)
+
+type difficulty struct {
+	name  string
+	curve SpeedCurve
+}
+
+var difficulties = map[Difficulty]difficulty{
+	Easy: {name: "Easy", curve: SpeedFormula{Start: 0.3, Step: 0.001, Min: 0.2}},
+	Normal: {name: "Normal", curve: SpeedTable{
+		{Size: 1, Speed: 0.2},
+		{Size: 10, Speed: 0.175},
+		{Size: 25, Speed: 0.15},
+		{Size: 50, Speed: 0.125},
+		{Size: 100, Speed: 0.1},
+	}},
+	Hard: {name: "Hard", curve: SpeedTable{
+		{Size: 1, Speed: 0.15},
+		{Size: 10, Speed: 0.125},
+		{Size: 20, Speed: 0.1},
+		{Size: 40, Speed: 0.075},
+	}},
+	Insane: {name: "Insane", curve: SpeedFormula{Start: 0.1, Step: 0.002, Min: 0.05}},
+}
+
+func (difficulty Difficulty) String() string {
+	return difficulties[difficulty].name
+}
+
+func (difficulty Difficulty) Next() Difficulty {
+	return (difficulty + 1) % Difficulty(len(difficulties))
+}
+
+func (difficulty Difficulty) Previous() Difficulty {
+	return (difficulty + Difficulty(len(difficulties)) - 1) % Difficulty(len(difficulties))
+}
+
+func (difficulty Difficulty) MarshalText() ([]byte, error) {
+	preset, ok := difficulties[difficulty]
+	if !ok {
+		return nil, fmt.Errorf("unknown difficulty %d", difficulty)
+	}
+
+	return []byte(preset.name), nil
+}
+
+func (difficulty *Difficulty) UnmarshalText(text []byte) error {
+	for value, preset := range difficulties {
+		if preset.name == string(text) {
+			*difficulty = value
+			return nil
+		}
+	}
+
+	return fmt.Errorf("unknown difficulty %q", text)
+}
+
+// SetDifficulty changes the speed curve from the next game, or right away
+// when no game is running.
+func (board *Board) SetDifficulty(difficulty Difficulty) {
+	board.setGameplay(func(next *gameplay) {
+		next.difficulty = difficulty
+	})
+}
+
+func (board *Board) Difficulty() Difficulty {
+	return board.next.difficulty
+}
+
+func (board *Board) SetSpeedCurve(curve SpeedCurve) {
+	board.curve = curve
+	board.speed = curve.Speed(board.snake.Size())
+}
+
+func (board *Board) accelerate() {
+	if board.curve != nil {
+		board.speed = board.curve.Speed(board.snake.Size())
+	}
+}
+
+func (board *Board) CellsPerSecond() float32 {
+	return float32(FramesPerSecond) / float32(board.moveInterval())
+}
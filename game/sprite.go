@@ -0,0 +1,188 @@
+package game
+
+import (
+	"fmt"
+	"log"
+
+	rl "github.com/gen2brain/raylib-go/raylib"
+)
+
+// AtlasPath is the sprite atlas, relative to the working directory like the
+// other assets.
+const AtlasPath = "assets/snake-atlas.png"
+
+// Sprites of the atlas, one square cell each in a row. They are drawn
+// for a snake going up and in grey levels, tinted with the theme colors.
+const (
+	headSprite     = 0
+	straightSprite = 1
+	cornerSprite   = 2 // joins the bottom and the right sides
+	tailSprite     = 3 // joins the top side
+	appleSprite    = 4
+)
+
+type Atlas struct {
+	texture rl.Texture2D
+	cell    float32
+}
+
+var atlases = map[string]*Atlas{}
+
+// LoadAtlas loads the atlas at path once, the boards share its texture.
+func LoadAtlas(path string) (*Atlas, error) {
+	if atlas, ok := atlases[path]; ok {
+		return atlas, nil
+	}
+
+	texture := rl.LoadTexture(path)
+	if texture.ID == 0 {
+		return nil, fmt.Errorf("can't load the sprite atlas %s", path)
+	}
+
+	atlas := &Atlas{texture: texture, cell: float32(texture.Height)}
+	atlases[path] = atlas
+
+	return atlas, nil
+}
+
+// draw draws sprite on the cell at x, y of size pixels, rotated clockwise by
+// rotation degrees.
+func (atlas *Atlas) draw(sprite int, x int32, y int32, size float32, rotation float32, tint rl.Color) {
+	rl.DrawTexturePro(
+		atlas.texture,
+		rl.NewRectangle(float32(sprite)*atlas.cell, 0, atlas.cell, atlas.cell),
+		rl.NewRectangle(float32(x)+size/2, float32(y)+size/2, size, size),
+		rl.NewVector2(size/2, size/2),
+		rotation,
+		tint,
+	)
+}
+
+// SetSprites switches between the sprites and the plain rectangles, the
+// atlas is loaded on the first drawing as it needs the window.
+func (board *Board) SetSprites(enabled bool) {
+	board.sprites = enabled
+	if !enabled {
+		board.snake.atlas = nil
+	}
+}
+
+// atlas returns the atlas to draw with, nil for rectangles. A missing atlas
+// falls back on rectangles.
+func (board *Board) atlas() *Atlas {
+	if !board.sprites {
+		return nil
+	}
+
+	if board.snake.atlas == nil {
+		atlas, err := LoadAtlas(AtlasPath)
+		if err != nil {
+			log.Println(err)
+			board.sprites = false
+			return nil
+		}
+		board.snake.atlas = atlas
+	}
+
+	return board.snake.atlas
+}
+
+var rotations = map[Direction]float32{
+	Up:    0,
+	Right: 90,
+	Down:  180,
+	Left:  270,
+}
+
+// side returns the side of from where to lies, to being next to from or
+// across a wrapping wall.
+func side(from Position, to Position) Direction {
+	dx := to.x - from.x
+	dy := to.y - from.y
+
+	if dx > 1 || dx < -1 {
+		dx = -dx
+	}
+
+	if dy > 1 || dy < -1 {
+		dy = -dy
+	}
+
+	switch {
+	case dx > 0:
+		return Right
+	case dx < 0:
+		return Left
+	case dy > 0:
+		return Down
+	}
+
+	return Up
+}
+
+// cornerRotations turn the corner sprite, joining the bottom and the right,
+// toward the two sides of a bent segment.
+var cornerRotations = map[[2]Direction]float32{
+	{Down, Right}: 0,
+	{Down, Left}:  90,
+	{Up, Left}:    180,
+	{Up, Right}:   270,
+}
+
+// sprite picks the sprite of the segment at index and its rotation from the
+// segments before and after it in the ring buffer.
+func (snake *Snake) sprite(index int) (int, float32) {
+	coord := snake.getBody(index)
+
+	if index == snake.head {
+		if snake.length == 1 {
+			return headSprite, rotations[snake.direction]
+		}
+
+		return headSprite, rotations[opposites[side(coord, snake.getBody(index-1))]]
+	}
+
+	toHead := side(coord, snake.getBody(index+1))
+	if index == snake.tail() {
+		return tailSprite, rotations[toHead]
+	}
+
+	toTail := side(coord, snake.getBody(index-1))
+	if toHead == opposites[toTail] {
+		if toHead == Up || toHead == Down {
+			return straightSprite, 0
+		}
+
+		return straightSprite, 90
+	}
+
+	if toHead == Up || toHead == Down {
+		return cornerSprite, cornerRotations[[2]Direction{toHead, toTail}]
+	}
+
+	return cornerSprite, cornerRotations[[2]Direction{toTail, toHead}]
+}
+
+// drawSprites draws the snake with the atlas, tinted along the theme
+// gradient, a segment digesting an apple being drawn larger.
+func (snake *Snake) drawSprites(size int32) {
+	degradedStep := snake.degradedStep()
+
+	for index := snake.tail(); index <= snake.head; index++ {
+		coord := snake.getBody(index)
+		sprite, rotation := snake.sprite(index)
+		tint := fade(snake.theme.SnakeHead, snake.theme.SnakeTail, float64(degradedStep*(snake.head-index))/140, snake.opacity)
+
+		x := snake.coordinateConverter.XToPixel(coord.x)
+		y := snake.coordinateConverter.YToPixel(coord.y)
+		cell := float32(size)
+
+		if index != snake.head && snake.digesting(index) {
+			x -= size / 8
+			y -= size / 8
+			cell += float32(size / 4)
+		}
+
+		snake.atlas.draw(sprite, x, y, cell, rotation, tint)
+	}
+}
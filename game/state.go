@@ -0,0 +1,505 @@
+package game
+
+import (
+	"errors"
+	"fmt"
+)
+
+var errEmptySnake = errors.New("a snake needs at least one cell")
+
+type Cell struct {
+	X int32 `json:"x"`
+	Y int32 `json:"y"`
+}
+
+type FoodCell struct {
+	Cell
+	Kind      FoodKind `json:"kind"`
+	TicksLeft int      `json:"ticksLeft,omitempty"`
+}
+
+type HazardCell struct {
+	Cell
+	Kind      HazardKind `json:"kind"`
+	TicksLeft int        `json:"ticksLeft"`
+	Path      []Cell     `json:"path,omitempty"`
+	Step      int        `json:"step,omitempty"`
+	Backward  bool       `json:"backward,omitempty"`
+}
+
+type PowerUpCell struct {
+	Cell
+	Kind      PowerUpKind `json:"kind"`
+	TicksLeft int         `json:"ticksLeft"`
+}
+
+// DigestionCell is a bulge along the snake, Segment counting from the head.
+type DigestionCell struct {
+	Segment int  `json:"segment"`
+	Growth  int  `json:"growth"`
+	Owed    bool `json:"owed,omitempty"`
+}
+
+type EffectState struct {
+	Kind      PowerUpKind `json:"kind"`
+	TicksLeft int         `json:"ticksLeft"`
+	Charges   int         `json:"charges"`
+}
+
+type State struct {
+	Grid           int32           `json:"grid"`
+	Snake          []Cell          `json:"snake"`
+	Direction      Direction       `json:"direction"`
+	Heading        Direction       `json:"heading"`
+	Digestion      []DigestionCell `json:"digestion,omitempty"`
+	ToGrow         int             `json:"toGrow,omitempty"`
+	Foods          []FoodCell      `json:"foods"`
+	Hazards        []HazardCell    `json:"hazards"`
+	PowerUps       []PowerUpCell   `json:"powerUps"`
+	Effects        []EffectState   `json:"effects"`
+	Difficulty     Difficulty      `json:"difficulty"`
+	WallMode       WallMode        `json:"wallMode"`
+	TailCut        bool            `json:"tailCut"`
+	DropCut        bool            `json:"dropCut,omitempty"`
+	GrowAtTail     bool            `json:"growAtTail"`
+	Speed          float32         `json:"speed"`
+	CellsPerSecond float32         `json:"cellsPerSecond"`
+	Mode           GameMode        `json:"mode"`
+	Hud            string          `json:"hud"`
+	Seconds        int32           `json:"seconds"`
+	Frames         int32           `json:"frames"`
+	MoveFrames     int32           `json:"moveFrames"`
+	SpeedTicks     int             `json:"speedTicks,omitempty"`
+	HazardTicks    int             `json:"hazardTicks"`
+	PowerUpTicks   int             `json:"powerUpTicks"`
+	Ticks          int             `json:"ticks"`
+	ApplesEaten    int             `json:"applesEaten"`
+	Status         Status          `json:"status"`
+	Score          int             `json:"score"`
+	Death          DeathCause      `json:"death"`
+}
+
+var statusNames = map[Status]string{
+	Victory:  "victory",
+	GameOver: "gameover",
+	Continue: "continue",
+	NewGame:  "newgame",
+	Pause:    "pause",
+}
+
+var directionNames = map[Direction]string{
+	Left:  "left",
+	Right: "right",
+	Up:    "up",
+	Down:  "down",
+}
+
+func (status Status) String() string {
+	return statusNames[status]
+}
+
+func (status Status) MarshalText() ([]byte, error) {
+	name, ok := statusNames[status]
+	if !ok {
+		return nil, fmt.Errorf("unknown status %d", status)
+	}
+
+	return []byte(name), nil
+}
+
+func (status *Status) UnmarshalText(text []byte) error {
+	for value, name := range statusNames {
+		if name == string(text) {
+			*status = value
+			return nil
+		}
+	}
+
+	return fmt.Errorf("unknown status %q", text)
+}
+
+func (direction Direction) String() string {
+	return directionNames[direction]
+}
+
+func (direction Direction) MarshalText() ([]byte, error) {
+	name, ok := directionNames[direction]
+	if !ok {
+		return nil, fmt.Errorf("unknown direction %d", direction)
+	}
+
+	return []byte(name), nil
+}
+
+func (direction *Direction) UnmarshalText(text []byte) error {
+	for value, name := range directionNames {
+		if name == string(text) {
+			*direction = value
+			return nil
+		}
+	}
+
+	return fmt.Errorf("unknown direction %q", text)
+}
+
+func (board *Board) State() State {
+	return State{
+		Grid:           board.grid,
+		Snake:          board.snake.Cells(),
+		Direction:      board.snake.direction,
+		Heading:        board.snake.heading,
+		Digestion:      board.snake.digestionCells(),
+		ToGrow:         board.snake.toGrow,
+		Foods:          board.foodCells(),
+		Status:         board.status,
+		Score:          board.Score(),
+		Hazards:        board.hazardCells(),
+		Death:          board.deathCause,
+		PowerUps:       board.powerUpCells(),
+		Effects:        board.effectStates(),
+		Difficulty:     board.difficulty,
+		WallMode:       board.wallMode,
+		TailCut:        board.tailCut,
+		DropCut:        board.dropCutSegments,
+		GrowAtTail:     board.snake.growAtTail,
+		Speed:          board.speed,
+		CellsPerSecond: board.CellsPerSecond(),
+		Mode:           board.mode,
+		Hud:            board.rules().Hud(board),
+		Seconds:        board.frames / FramesPerSecond,
+		Frames:         board.frames,
+		MoveFrames:     board.moveFrames,
+		SpeedTicks:     board.speedTicks,
+		HazardTicks:    board.hazardTicks,
+		PowerUpTicks:   board.powerUpTicks,
+		Ticks:          board.ticks,
+		ApplesEaten:    board.applesEaten,
+	}
+}
+
+// SetState lays the state out on the board, the rules it was played with
+// included: they become the settings of the next games too, until changed.
+func (board *Board) SetState(state State) error {
+	if len(state.Snake) == 0 {
+		return errEmptySnake
+	}
+
+	if state.Grid >= 3 && state.Grid != board.grid {
+		board.resize(state.Grid)
+	}
+	board.nextGrid = board.grid
+
+	if err := board.snake.Load(state.Snake, state.Direction); err != nil {
+		return err
+	}
+	board.snake.loadDigestion(state.Heading, state.Digestion, state.ToGrow)
+	board.foods = board.foods[:0]
+	for _, food := range state.Foods {
+		spawned := newFood(food.Kind, food.X, food.Y)
+		spawned.ticksLeft = food.TicksLeft
+		board.foods = append(board.foods, spawned)
+	}
+	board.hazards = board.hazards[:0]
+	for _, hazard := range state.Hazards {
+		path := make([]Position, 0, len(hazard.Path))
+		for _, cell := range hazard.Path {
+			path = append(path, newPosition(cell.X, cell.Y))
+		}
+
+		board.hazards = append(board.hazards, Hazard{
+			position:  newPosition(hazard.X, hazard.Y),
+			kind:      hazard.Kind,
+			ticksLeft: hazard.TicksLeft,
+			path:      path,
+			step:      hazard.Step,
+			backward:  hazard.Backward,
+		})
+	}
+
+	board.powerUps = board.powerUps[:0]
+	for _, powerUp := range state.PowerUps {
+		board.powerUps = append(board.powerUps, PowerUp{
+			position:  newPosition(powerUp.X, powerUp.Y),
+			kind:      powerUp.Kind,
+			ticksLeft: powerUp.TicksLeft,
+		})
+	}
+
+	board.effects = board.effects[:0]
+	for _, effect := range state.Effects {
+		board.effects = append(board.effects, Effect{
+			kind:      effect.Kind,
+			ticksLeft: effect.TicksLeft,
+			charges:   effect.Charges,
+		})
+	}
+
+	board.mode = state.Mode
+	board.frames = state.Frames
+	board.moveFrames = state.MoveFrames
+	board.speedTicks = state.SpeedTicks
+	board.hazardTicks = state.HazardTicks
+	board.powerUpTicks = state.PowerUpTicks
+	board.ticks = state.Ticks
+	board.applesEaten = state.ApplesEaten
+	board.difficulty = state.Difficulty
+	board.curve = difficulties[state.Difficulty].curve
+	board.speed = state.Speed
+	board.status = state.Status
+	board.deathCause = state.Death
+
+	board.next = gameplay{
+		difficulty:      state.Difficulty,
+		wallMode:        state.WallMode,
+		tailCut:         state.TailCut,
+		dropCutSegments: state.DropCut,
+		growAtTail:      state.GrowAtTail,
+	}
+	board.wallMode = state.WallMode
+	board.tailCut = state.TailCut
+	board.dropCutSegments = state.DropCut
+	board.snake.SetGrowAtTail(state.GrowAtTail)
+
+	return nil
+}
+
+func (board *Board) foodCells() []FoodCell {
+	cells := make([]FoodCell, 0, len(board.foods))
+
+	for _, food := range board.foods {
+		cells = append(cells, FoodCell{
+			Cell:      Cell{X: food.x, Y: food.y},
+			Kind:      food.kind,
+			TicksLeft: food.ticksLeft,
+		})
+	}
+
+	return cells
+}
+
+func (board *Board) hazardCells() []HazardCell {
+	cells := make([]HazardCell, 0, len(board.hazards))
+
+	for _, hazard := range board.hazards {
+		var path []Cell
+		for _, position := range hazard.path {
+			path = append(path, Cell{X: position.x, Y: position.y})
+		}
+
+		cells = append(cells, HazardCell{
+			Cell:      Cell{X: hazard.position.x, Y: hazard.position.y},
+			Kind:      hazard.kind,
+			TicksLeft: hazard.ticksLeft,
+			Path:      path,
+			Step:      hazard.step,
+			Backward:  hazard.backward,
+		})
+	}
+
+	return cells
+}
+
+func (board *Board) powerUpCells() []PowerUpCell {
+	cells := make([]PowerUpCell, 0, len(board.powerUps))
+
+	for _, powerUp := range board.powerUps {
+		cells = append(cells, PowerUpCell{
+			Cell:      Cell{X: powerUp.position.x, Y: powerUp.position.y},
+			Kind:      powerUp.kind,
+			TicksLeft: powerUp.ticksLeft,
+		})
+	}
+
+	return cells
+}
+
+func (board *Board) effectStates() []EffectState {
+	effects := make([]EffectState, 0, len(board.effects))
+
+	for _, effect := range board.effects {
+		effects = append(effects, EffectState{
+			Kind:      effect.kind,
+			TicksLeft: effect.ticksLeft,
+			Charges:   effect.charges,
+		})
+	}
+
+	return effects
+}
+
+func (snake *Snake) Cells() []Cell {
+	cells := make([]Cell, 0, snake.length)
+
+	for index := snake.head; index >= snake.head-(snake.length-1); index-- {
+		cells = append(cells, Cell{X: snake.getBody(index).x, Y: snake.getBody(index).y})
+	}
+
+	return cells
+}
+
+func (snake *Snake) Load(cells []Cell, direction Direction) error {
+	if len(cells) == 0 {
+		return errEmptySnake
+	}
+
+	if len(cells) > len(snake.body) {
+		cells = cells[:len(snake.body)]
+	}
+
+	snake.head = len(cells) - 1
+	snake.length = len(cells)
+	snake.direction = direction
+	snake.heading = direction
+	snake.toGrow = 0
+	snake.hasMoved = false
+
+	for index := range snake.digestion {
+		snake.digestion[index] = bulge{}
+	}
+
+	for index, cell := range cells {
+		snake.setBody(snake.head-index, newPosition(cell.X, cell.Y))
+	}
+
+	return nil
+}
+
+func (snake *Snake) digestionCells() []DigestionCell {
+	var cells []DigestionCell
+
+	for index := snake.head; index >= snake.tail(); index-- {
+		if digestion := snake.digestion[index%len(snake.digestion)]; digestion != (bulge{}) {
+			cells = append(cells, DigestionCell{
+				Segment: snake.head - index,
+				Growth:  digestion.growth,
+				Owed:    digestion.owed,
+			})
+		}
+	}
+
+	return cells
+}
+
+// loadDigestion restores what Load can't tell from the cells: where the
+// snake last moved and what it is still digesting.
+func (snake *Snake) loadDigestion(heading Direction, cells []DigestionCell, toGrow int) {
+	snake.heading = heading
+	snake.toGrow = toGrow
+
+	for _, cell := range cells {
+		if cell.Segment < 0 || cell.Segment >= snake.length {
+			continue
+		}
+
+		snake.digestion[(snake.head-cell.Segment)%len(snake.digestion)] = bulge{growth: cell.Growth, owed: cell.Owed}
+	}
+}
+
+// SameView tells whether both states draw the same picture, the counters
+// ticking every frame aside. Streams compare states with it to only send
+// the ones a viewer would notice.
+func (state State) SameView(other State) bool {
+	return state.Grid == other.Grid &&
+		state.Direction == other.Direction &&
+		state.Status == other.Status &&
+		state.Score == other.Score &&
+		state.Seconds == other.Seconds &&
+		state.CellsPerSecond == other.CellsPerSecond &&
+		state.Difficulty == other.Difficulty &&
+		state.Mode == other.Mode &&
+		state.Hud == other.Hud &&
+		state.Death == other.Death &&
+		sameCells(state.Snake, other.Snake) &&
+		sameFoods(state.Foods, other.Foods) &&
+		sameHazards(state.Hazards, other.Hazards) &&
+		samePowerUps(state.PowerUps, other.PowerUps) &&
+		sameEffects(state.Effects, other.Effects) &&
+		sameDigestion(state.Digestion, other.Digestion)
+}
+
+func sameCells(cells []Cell, others []Cell) bool {
+	if len(cells) != len(others) {
+		return false
+	}
+
+	for index := range cells {
+		if cells[index] != others[index] {
+			return false
+		}
+	}
+
+	return true
+}
+
+func sameFoods(foods []FoodCell, others []FoodCell) bool {
+	if len(foods) != len(others) {
+		return false
+	}
+
+	for index := range foods {
+		if foods[index] != others[index] {
+			return false
+		}
+	}
+
+	return true
+}
+
+// sameHazards leaves the patrol paths out, they don't change while the
+// hazard lives.
+func sameHazards(hazards []HazardCell, others []HazardCell) bool {
+	if len(hazards) != len(others) {
+		return false
+	}
+
+	for index, hazard := range hazards {
+		other := others[index]
+		if hazard.Cell != other.Cell || hazard.Kind != other.Kind || hazard.TicksLeft != other.TicksLeft {
+			return false
+		}
+	}
+
+	return true
+}
+
+func samePowerUps(powerUps []PowerUpCell, others []PowerUpCell) bool {
+	if len(powerUps) != len(others) {
+		return false
+	}
+
+	for index := range powerUps {
+		if powerUps[index] != others[index] {
+			return false
+		}
+	}
+
+	return true
+}
+
+func sameEffects(effects []EffectState, others []EffectState) bool {
+	if len(effects) != len(others) {
+		return false
+	}
+
+	for index := range effects {
+		if effects[index] != others[index] {
+			return false
+		}
+	}
+
+	return true
+}
+
+func sameDigestion(digestion []DigestionCell, others []DigestionCell) bool {
+	if len(digestion) != len(others) {
+		return false
+	}
+
+	for index := range digestion {
+		if digestion[index] != others[index] {
+			return false
+		}
+	}
+
+	return true
+}
@@ -0,0 +1,119 @@
+package game
+
+import (
+	"encoding/json"
+	"math/rand"
+	"reflect"
+	"testing"
+)
+
+// playing returns a board in the middle of a game, with a patrol, a power
+// up, an effect and a bulge being digested.
+func playing(t *testing.T) *Board {
+	board := newTestBoard()
+	board.SetGrowAtTail(true)
+	board.Start()
+
+	// A new game seeds the random numbers with the clock, the snake and
+	// what spawns are laid out the same way every run from here.
+	rand.Seed(1)
+	board.snake.Load([]Cell{{X: 3, Y: 10}}, Right)
+	board.foods = []Food{newFood(NormalApple, 15, 2)}
+
+	board.spawnHazard(Patrol)
+	board.spawnPowerUp(Shield)
+	board.effects = append(board.effects, Effect{kind: SlowMotion, ticksLeft: 12})
+	board.speedTicks = 5
+	board.snake.Grow(10)
+
+	for frame := 0; frame < 60; frame++ {
+		if frame == 45 {
+			board.snake.AppleEated(2)
+		}
+		board.Tick()
+	}
+
+	if board.Status() != Continue || len(board.hazards) == 0 || len(board.hazards[0].path) < 2 || len(board.snake.digestionCells()) == 0 {
+		t.Fatalf("status %s, hazards %v", board.Status(), board.hazards)
+	}
+
+	return board
+}
+
+func TestStateRoundTrip(t *testing.T) {
+	board := playing(t)
+
+	data, err := json.Marshal(board.State())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var saved State
+	if err := json.Unmarshal(data, &saved); err != nil {
+		t.Fatal(err)
+	}
+
+	restored := newTestBoard()
+	restored.SetGrowAtTail(true)
+	if err := restored.SetState(saved); err != nil {
+		t.Fatal(err)
+	}
+
+	if !reflect.DeepEqual(restored.State(), board.State()) {
+		t.Fatalf("restored %+v\nwant %+v", restored.State(), board.State())
+	}
+
+	// Both games go on the same way, what spawns depending on the same
+	// random numbers.
+	for _, game := range []*Board{board, restored} {
+		rand.Seed(2)
+		for frame := 0; frame < 120; frame++ {
+			game.Tick()
+		}
+	}
+
+	if !reflect.DeepEqual(restored.State(), board.State()) {
+		t.Fatalf("after ticking restored %+v\nwant %+v", restored.State(), board.State())
+	}
+}
+
+func TestStateKeepsTheRules(t *testing.T) {
+	board := playing(t)
+	board.SetWallMode(WrappedWalls)
+	board.SetDifficulty(Hard)
+	board.Start()
+	saved := board.State()
+
+	restored := newTestBoard()
+	restored.SetGrid(15)
+	if err := restored.SetState(saved); err != nil {
+		t.Fatal(err)
+	}
+
+	if restored.wallMode != WrappedWalls || restored.difficulty != Hard || !restored.snake.growAtTail {
+		t.Fatal("the saved game should be played with its own rules")
+	}
+
+	restored.NewGame()
+
+	if restored.Grid() != 20 || restored.wallMode != WrappedWalls || restored.difficulty != Hard {
+		t.Fatal("the next game should keep the rules of the restored one")
+	}
+}
+
+func TestStateWithoutSnake(t *testing.T) {
+	board := newTestBoard()
+	board.snake.Load([]Cell{{X: 4, Y: 4}}, Up)
+
+	if err := board.SetState(State{Grid: 10}); err == nil {
+		t.Fatal("a state without snake should be refused")
+	}
+
+	if board.grid != 20 || board.snake.getBody(board.snake.head) != newPosition(4, 4) {
+		t.Fatal("a refused state changed the board")
+	}
+
+	if err := board.snake.Load(nil, Up); err == nil {
+		t.Fatal("loading a snake without cells should fail")
+	}
+}
@@ -0,0 +1,37 @@
+package game
+
+const droppedFoodLifetime = 40
+
+func (board *Board) SetTailCut(enabled bool, dropAsFood bool) {
+	board.setGameplay(func(next *gameplay) {
+		next.tailCut = enabled
+		next.dropCutSegments = dropAsFood
+	})
+}
+
+func (board *Board) TailCut() (bool, bool) {
+	return board.next.tailCut, board.next.dropCutSegments
+}
+
+// cutTail cuts the snake where the head bites the body. The bitten segment
+// is lost with everything behind it and, when enabled, the lost segments
+// are left on the board as apples.
+func (board *Board) cutTail() bool {
+	index, bitten := board.snake.Bite()
+	if !bitten {
+		return false
+	}
+
+	head := board.snake.getBody(board.snake.head)
+	for _, position := range board.snake.CutAt(index) {
+		if !board.dropCutSegments || position == head || board.hasFoodAt(position.x, position.y) {
+			continue
+		}
+
+		food := newFood(NormalApple, position.x, position.y)
+		food.ticksLeft = droppedFoodLifetime
+		board.foods = append(board.foods, food)
+	}
+
+	return true
+}
@@ -0,0 +1,284 @@
+package game
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"strings"
+
+	rl "github.com/gen2brain/raylib-go/raylib"
+)
+
+// Theme holds the colors of the board and the snake, the snake fading from
+// SnakeHead to SnakeTail along its body.
+type Theme struct {
+	Name       string
+	Background rl.Color
+	Border     rl.Color
+	Floor      rl.Color
+	GridLines  rl.Color
+	Apple      rl.Color
+	SnakeHead  rl.Color
+	SnakeTail  rl.Color
+	Text       rl.Color
+}
+
+// themeFile is a theme as written in a file, colors being "#rrggbb" or
+// "#rrggbbaa" and missing ones taken from the classic theme.
+type themeFile struct {
+	Name       string `json:"name"`
+	Background string `json:"background"`
+	Border     string `json:"border"`
+	Floor      string `json:"floor"`
+	GridLines  string `json:"gridLines"`
+	Apple      string `json:"apple"`
+	SnakeHead  string `json:"snakeHead"`
+	SnakeTail  string `json:"snakeTail"`
+	Text       string `json:"text"`
+}
+
+var ClassicTheme = Theme{
+	Name:       "Classic",
+	Background: rl.White,
+	Border:     rl.NewColor(66, 66, 66, 255),
+	Floor:      rl.White,
+	GridLines:  rl.NewColor(0, 0, 0, 0),
+	Apple:      rl.NewColor(192, 70, 67, 255),
+	SnakeHead:  rl.NewColor(47, 78, 0, 255),
+	SnakeTail:  rl.NewColor(187, 218, 140, 255),
+	Text:       rl.DarkGray,
+}
+
+var themes = []Theme{
+	ClassicTheme,
+	{
+		Name:       "Dark",
+		Background: rl.NewColor(24, 24, 28, 255),
+		Border:     rl.NewColor(10, 10, 12, 255),
+		Floor:      rl.NewColor(40, 42, 48, 255),
+		GridLines:  rl.NewColor(55, 58, 66, 255),
+		Apple:      rl.NewColor(235, 90, 80, 255),
+		SnakeHead:  rl.NewColor(120, 220, 90, 255),
+		SnakeTail:  rl.NewColor(30, 90, 40, 255),
+		Text:       rl.NewColor(210, 210, 215, 255),
+	},
+	{
+		Name:       "Grass",
+		Background: rl.NewColor(236, 232, 214, 255),
+		Border:     rl.NewColor(98, 70, 46, 255),
+		Floor:      rl.NewColor(170, 215, 81, 255),
+		GridLines:  rl.NewColor(162, 209, 73, 255),
+		Apple:      rl.NewColor(231, 71, 29, 255),
+		SnakeHead:  rl.NewColor(48, 84, 196, 255),
+		SnakeTail:  rl.NewColor(120, 160, 240, 255),
+		Text:       rl.NewColor(70, 50, 30, 255),
+	},
+	{
+		Name:       "Ocean",
+		Background: rl.NewColor(12, 35, 64, 255),
+		Border:     rl.NewColor(6, 20, 40, 255),
+		Floor:      rl.NewColor(18, 70, 110, 255),
+		GridLines:  rl.NewColor(25, 82, 125, 255),
+		Apple:      rl.NewColor(255, 170, 60, 255),
+		SnakeHead:  rl.NewColor(240, 250, 255, 255),
+		SnakeTail:  rl.NewColor(90, 200, 220, 255),
+		Text:       rl.NewColor(200, 230, 250, 255),
+	},
+	{
+		Name:       "Retro",
+		Background: rl.NewColor(155, 188, 15, 255),
+		Border:     rl.NewColor(15, 56, 15, 255),
+		Floor:      rl.NewColor(139, 172, 15, 255),
+		GridLines:  rl.NewColor(0, 0, 0, 0),
+		Apple:      rl.NewColor(48, 98, 48, 255),
+		SnakeHead:  rl.NewColor(15, 56, 15, 255),
+		SnakeTail:  rl.NewColor(48, 98, 48, 255),
+		Text:       rl.NewColor(15, 56, 15, 255),
+	},
+}
+
+// The accessibility palettes keep the snake and the apple apart for each
+// kind of color blindness, they read best with the shape cues on.
+var accessibleThemes = []Theme{
+	{
+		Name:       "Deuteranopia",
+		Background: rl.White,
+		Border:     rl.NewColor(50, 50, 50, 255),
+		Floor:      rl.NewColor(245, 245, 245, 255),
+		GridLines:  rl.NewColor(0, 0, 0, 0),
+		Apple:      rl.NewColor(230, 159, 0, 255),
+		SnakeHead:  rl.NewColor(0, 74, 140, 255),
+		SnakeTail:  rl.NewColor(86, 180, 233, 255),
+		Text:       rl.NewColor(30, 30, 30, 255),
+	},
+	{
+		Name:       "Protanopia",
+		Background: rl.White,
+		Border:     rl.NewColor(50, 50, 50, 255),
+		Floor:      rl.NewColor(245, 245, 245, 255),
+		GridLines:  rl.NewColor(0, 0, 0, 0),
+		Apple:      rl.NewColor(255, 194, 10, 255),
+		SnakeHead:  rl.NewColor(12, 60, 150, 255),
+		SnakeTail:  rl.NewColor(130, 170, 235, 255),
+		Text:       rl.NewColor(30, 30, 30, 255),
+	},
+	{
+		Name:       "Tritanopia",
+		Background: rl.White,
+		Border:     rl.NewColor(50, 50, 50, 255),
+		Floor:      rl.NewColor(245, 245, 245, 255),
+		GridLines:  rl.NewColor(0, 0, 0, 0),
+		Apple:      rl.NewColor(220, 40, 40, 255),
+		SnakeHead:  rl.NewColor(0, 95, 95, 255),
+		SnakeTail:  rl.NewColor(150, 215, 215, 255),
+		Text:       rl.NewColor(30, 30, 30, 255),
+	},
+	{
+		Name:       "High contrast",
+		Background: rl.Black,
+		Border:     rl.White,
+		Floor:      rl.Black,
+		GridLines:  rl.NewColor(70, 70, 70, 255),
+		Apple:      rl.NewColor(255, 230, 0, 255),
+		SnakeHead:  rl.White,
+		SnakeTail:  rl.NewColor(0, 200, 255, 255),
+		Text:       rl.White,
+	},
+}
+
+func init() {
+	themes = append(themes, accessibleThemes...)
+}
+
+// ThemeNames returns the built-in themes followed by the loaded ones.
+func ThemeNames() []string {
+	var names []string
+	for _, theme := range themes {
+		names = append(names, theme.Name)
+	}
+
+	return names
+}
+
+// FindTheme returns the theme named name, the classic one when it is
+// unknown.
+func FindTheme(name string) (Theme, bool) {
+	for _, theme := range themes {
+		if theme.Name == name {
+			return theme, true
+		}
+	}
+
+	return ClassicTheme, false
+}
+
+// RegisterTheme makes theme selectable, replacing the one of the same name.
+func RegisterTheme(theme Theme) {
+	for index := range themes {
+		if themes[index].Name == theme.Name {
+			themes[index] = theme
+			return
+		}
+	}
+
+	themes = append(themes, theme)
+}
+
+// LoadTheme reads a theme file.
+func LoadTheme(path string) (Theme, error) {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return Theme{}, err
+	}
+
+	var file themeFile
+	if err := json.Unmarshal(data, &file); err != nil {
+		return Theme{}, fmt.Errorf("%s: %w", path, err)
+	}
+
+	if file.Name == "" {
+		return Theme{}, fmt.Errorf("%s: a theme needs a name", path)
+	}
+
+	theme := ClassicTheme
+	theme.Name = file.Name
+
+	for _, color := range []struct {
+		text  string
+		color *rl.Color
+	}{
+		{file.Background, &theme.Background},
+		{file.Border, &theme.Border},
+		{file.Floor, &theme.Floor},
+		{file.GridLines, &theme.GridLines},
+		{file.Apple, &theme.Apple},
+		{file.SnakeHead, &theme.SnakeHead},
+		{file.SnakeTail, &theme.SnakeTail},
+		{file.Text, &theme.Text},
+	} {
+		if color.text == "" {
+			continue
+		}
+
+		if *color.color, err = parseColor(color.text); err != nil {
+			return Theme{}, fmt.Errorf("%s: %w", path, err)
+		}
+	}
+
+	return theme, nil
+}
+
+func parseColor(text string) (rl.Color, error) {
+	var red, green, blue uint8
+	alpha := uint8(255)
+
+	hex := strings.TrimPrefix(text, "#")
+	switch len(hex) {
+	case 6:
+		if _, err := fmt.Sscanf(hex, "%02x%02x%02x", &red, &green, &blue); err != nil {
+			return rl.Color{}, fmt.Errorf("invalid color %q", text)
+		}
+	case 8:
+		if _, err := fmt.Sscanf(hex, "%02x%02x%02x%02x", &red, &green, &blue, &alpha); err != nil {
+			return rl.Color{}, fmt.Errorf("invalid color %q", text)
+		}
+	default:
+		return rl.Color{}, fmt.Errorf("invalid color %q", text)
+	}
+
+	return rl.NewColor(red, green, blue, alpha), nil
+}
+
+// fade returns the color ratio of the way from one color to the other.
+func fade(from rl.Color, to rl.Color, ratio float64, alpha uint8) rl.Color {
+	if ratio > 1 {
+		ratio = 1
+	}
+
+	return rl.NewColor(
+		uint8(float64(from.R)+(float64(to.R)-float64(from.R))*ratio),
+		uint8(float64(from.G)+(float64(to.G)-float64(from.G))*ratio),
+		uint8(float64(from.B)+(float64(to.B)-float64(from.B))*ratio),
+		alpha,
+	)
+}
+
+func withAlpha(color rl.Color, alpha uint8) rl.Color {
+	return rl.NewColor(color.R, color.G, color.B, alpha)
+}
+
+func (board *Board) SetTheme(theme Theme) {
+	board.theme = theme
+	board.snake.theme = theme
+}
+
+func (board *Board) Theme() Theme {
+	return board.theme
+}
+
+// SetShapeCues draws the apple as an outlined circle and eyes on the head so
+// that play doesn't depend on colors alone.
+func (board *Board) SetShapeCues(enabled bool) {
+	board.shapeCues = enabled
+	board.snake.shapeCues = enabled
+}
@@ -0,0 +1,64 @@
+package game
+
+import "fmt"
+
+type WallMode int
+
+const (
+	SolidWalls   WallMode = 0
+	WrappedWalls WallMode = 1
+)
+
+var wallModeNames = map[WallMode]string{
+	SolidWalls:   "Solid",
+	WrappedWalls: "Wrap",
+}
+
+func (mode WallMode) String() string {
+	return wallModeNames[mode]
+}
+
+func (mode WallMode) Next() WallMode {
+	return (mode + 1) % WallMode(len(wallModeNames))
+}
+
+func (mode WallMode) MarshalText() ([]byte, error) {
+	name, ok := wallModeNames[mode]
+	if !ok {
+		return nil, fmt.Errorf("unknown wall mode %d", mode)
+	}
+
+	return []byte(name), nil
+}
+
+func (mode *WallMode) UnmarshalText(text []byte) error {
+	for value, name := range wallModeNames {
+		if name == string(text) {
+			*mode = value
+			return nil
+		}
+	}
+
+	return fmt.Errorf("unknown wall mode %q", text)
+}
+
+func (board *Board) SetWallMode(mode WallMode) {
+	board.setGameplay(func(next *gameplay) {
+		next.wallMode = mode
+	})
+}
+
+func (board *Board) WallMode() WallMode {
+	return board.next.wallMode
+}
+
+// hitWall handles a head that left the grid, it returns false when the
+// snake really hit the wall.
+func (board *Board) hitWall() bool {
+	if board.wallMode == WrappedWalls {
+		board.snake.Wrap()
+		return true
+	}
+
+	return board.useShield()
+}
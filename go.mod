@@ -6,5 +6,6 @@ require (
 	github.com/faiface/pixel v0.10.0
 	github.com/gen2brain/raylib-go v0.0.0-20201123133337-d123299701ae
 	github.com/go-gl/glfw/v3.3/glfw v0.0.0-20201108214237-06ea97f0c265 // indirect
+	github.com/gorilla/websocket v1.4.2
 	golang.org/x/image v0.0.0-20190523035834-f03afa92d3ff
 )
@@ -0,0 +1,103 @@
+package highscore
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+)
+
+const tableSize = 10
+
+type Entry struct {
+	Score int       `json:"score"`
+	Date  time.Time `json:"date"`
+}
+
+// Store keeps one table per game mode, best scores first.
+type Store struct {
+	path   string
+	Tables map[string][]Entry `json:"tables"`
+}
+
+func DefaultPath() (string, error) {
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return "", err
+	}
+
+	return filepath.Join(dir, "goti-snake", "highscores.json"), nil
+}
+
+// Load reads the store at path, a missing file gives an empty store.
+func Load(path string) (*Store, error) {
+	store := &Store{
+		path:   path,
+		Tables: make(map[string][]Entry),
+	}
+
+	data, err := ioutil.ReadFile(path)
+	if os.IsNotExist(err) {
+		return store, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if err := json.Unmarshal(data, store); err != nil {
+		return nil, err
+	}
+
+	if store.Tables == nil {
+		store.Tables = make(map[string][]Entry)
+	}
+
+	return store, nil
+}
+
+// Save writes the store to its path, a store without one is kept in memory
+// only.
+func (store *Store) Save() error {
+	if store.path == "" {
+		return nil
+	}
+
+	data, err := json.MarshalIndent(store, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(filepath.Dir(store.path), 0755); err != nil {
+		return err
+	}
+
+	return ioutil.WriteFile(store.path, data, 0644)
+}
+
+// Add records score in the table of mode and returns its rank starting at 1,
+// or 0 when the score does not make it in the table.
+func (store *Store) Add(mode string, score int, date time.Time) int {
+	table := append(store.Tables[mode], Entry{Score: score, Date: date})
+	sort.SliceStable(table, func(i int, j int) bool {
+		return table[i].Score > table[j].Score
+	})
+
+	if len(table) > tableSize {
+		table = table[:tableSize]
+	}
+	store.Tables[mode] = table
+
+	for index, entry := range table {
+		if entry.Score == score && entry.Date.Equal(date) {
+			return index + 1
+		}
+	}
+
+	return 0
+}
+
+func (store *Store) Table(mode string) []Entry {
+	return store.Tables[mode]
+}
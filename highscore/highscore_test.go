@@ -0,0 +1,46 @@
+package highscore
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestSaveWithoutPathKeepsInMemory(t *testing.T) {
+	store, err := Load("")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	store.Add("Classic", 12, time.Now())
+
+	if err := store.Save(); err != nil {
+		t.Fatalf("saving a store without path: %v", err)
+	}
+}
+
+func TestSaveAndLoad(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "highscores.json")
+
+	store, err := Load(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if rank := store.Add("Classic", 12, time.Now()); rank != 1 {
+		t.Fatalf("rank = %d, want 1", rank)
+	}
+
+	if err := store.Save(); err != nil {
+		t.Fatal(err)
+	}
+
+	loaded, err := Load(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if entries := loaded.Tables["Classic"]; len(entries) != 1 || entries[0].Score != 12 {
+		t.Fatalf("loaded %+v", loaded.Tables)
+	}
+}
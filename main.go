@@ -1,77 +1,316 @@
 package main
 
 import (
+	"flag"
+	"log"
+	"net/http"
+	"path/filepath"
+	"sync"
+
+	"github.com/blackprism/goti-snake/api"
+	"github.com/blackprism/goti-snake/audio"
+	"github.com/blackprism/goti-snake/config"
+	"github.com/blackprism/goti-snake/controls"
 	gamePkg "github.com/blackprism/goti-snake/game"
+	"github.com/blackprism/goti-snake/highscore"
+	"github.com/blackprism/goti-snake/scene"
+	"github.com/blackprism/goti-snake/stream"
+	"github.com/blackprism/goti-snake/web"
 	"github.com/gen2brain/raylib-go/raylib"
 )
 
+var eventSounds = map[gamePkg.Event]audio.Sound{
+	gamePkg.Ate:    audio.Apple,
+	gamePkg.Turned: audio.Turn,
+	gamePkg.Died:   audio.Death,
+	gamePkg.Won:    audio.Victory,
+}
+
 const (
-	gameSize  = 600
-	border    = 20
-	gridSize  = 20
-	snakeSize = (gameSize - 2*border) / gridSize
+	gameSize = 600
+	border   = 20
+	gridSize = 20
 )
 
 func main() {
+	streamAddress := flag.String("stream", "", "address to stream the game to spectators, e.g. :4242")
+	apiAddress := flag.String("api", "", "address of the HTTP control API, e.g. :8080")
+	webAddress := flag.String("web", "", "serve the game to browsers instead of opening a window, e.g. :8000")
+	spectateAddress := flag.String("spectate", "", "address of a streamed game to watch, e.g. localhost:4242")
+	flag.Parse()
+
 	rl.SetTraceLog(rl.LogError)
 
 	if gridSize < 3 {
 		panic("GridSize should be at less 10")
 	}
 
-	position := gamePkg.NewCoordinateConverter(
-		20,
-		60,
-		snakeSize,
-	)
-	snake := gamePkg.NewSnake(gridSize, position)
-	snake.Init()
+	if *webAddress != "" {
+		log.Fatal(http.ListenAndServe(*webAddress, web.NewServer(func() *gamePkg.Board {
+			return newBoard(gridSize)
+		})))
+	}
 
-	game := gamePkg.NewBoard(snake, gameSize, gridSize, 20, 0.2, position)
+	if *spectateAddress != "" {
+		spectate(*spectateAddress)
+		return
+	}
+
+	var server *stream.Server
+	if *streamAddress != "" {
+		var err error
+		server, err = stream.Listen(*streamAddress)
+		if err != nil {
+			log.Fatal(err)
+		}
+		defer server.Close()
+	}
+
+	var control *api.Server
+	if *apiAddress != "" {
+		var err error
+		control, err = api.Listen(*apiAddress)
+		if err != nil {
+			log.Fatal(err)
+		}
+		defer control.Close()
+	}
+
+	loadThemes()
+	settings := loadConfig()
+
+	game := newBoard(settings.Grid)
+	settings.Apply(game)
 	game.Init()
-	game.SpawnApple()
+	game.SpawnFood()
+	resumeGame(game)
+
+	sounds := audio.Open()
+	defer sounds.Close()
+	game.SetListener(func(event gamePkg.Event) {
+		sounds.Play(eventSounds[event])
+	})
+
+	gamepads := controls.NewGamepads(controls.Raylib{})
+
+	scenes := scene.New(&scene.Context{
+		Board:    game,
+		Scores:   loadHighScores(),
+		Config:   settings,
+		NewBoard: newBoard,
+		Input:    scene.Keyboard{},
+		Window:   scene.RaylibWindow{},
+		Gamepads: gamepads,
+		Pointer:  controls.NewPointer(controls.Raylib{}),
+		Audio:    sounds,
+		Width:    gameSize,
+		Height:   gameSize + 40,
+	})
 
-	gameStatus := gamePkg.NewGame
+	for !scenes.Done() {
+		if control != nil {
+			control.Apply(game)
+		}
+
+		gamepads.Update()
+
+		if err := scenes.Update(); err != nil {
+			log.Println(err)
+		}
+
+		if server != nil {
+			server.Publish(game.State())
+		}
 
-	windowShouldBeClosed := false
+		if control != nil {
+			control.Publish(game.State())
+		}
+
+		sounds.SetVolume(settings.Volume)
+		sounds.SetMusic(settings.Music && game.Status() == gamePkg.Continue)
+		sounds.SetSpeed(game.CellsPerSecond())
+		sounds.Update()
 
-	for !windowShouldBeClosed {
 		rl.BeginDrawing()
+		scenes.Draw()
+		rl.EndDrawing()
 
-		if gameStatus == gamePkg.NewGame {
-			game.AskNewGame()
+		// WindowShouldClose blocks while the window is minimised, the game
+		// is paused before and the scenes follow it.
+		if rl.IsWindowMinimized() && game.Status() == gamePkg.Continue {
+			game.SetPaused(true)
 		}
 
-		for !windowShouldBeClosed {
-			if rl.WindowShouldClose() {
-				windowShouldBeClosed = true
-				break
+		if rl.WindowShouldClose() {
+			if err := scenes.Close(); err != nil {
+				log.Println(err)
 			}
+		}
+	}
 
-			gameStatus = game.Loop()
+	saveGame(game)
+	rl.CloseWindow()
+}
 
-			if gameStatus == gamePkg.NewGame {
-				rl.EndDrawing()
-				continue
-			}
+// resumeGame loads, paused, the game in progress when the player last quit.
+func resumeGame(game *gamePkg.Board) {
+	path, err := config.SavedGamePath()
+	if err != nil {
+		log.Println(err)
+		return
+	}
+
+	state, ok, err := config.LoadGame(path)
+	if err != nil {
+		log.Println(err)
+		return
+	}
+
+	if !ok {
+		return
+	}
+
+	if err := game.SetState(state); err != nil {
+		log.Println(err)
+		return
+	}
+	game.SetPaused(true)
+
+	if err := config.RemoveGame(path); err != nil {
+		log.Println(err)
+	}
+}
+
+// saveGame keeps the game in progress to resume it on the next launch.
+func saveGame(game *gamePkg.Board) {
+	if !game.Running() {
+		return
+	}
+
+	path, err := config.SavedGamePath()
+	if err == nil {
+		err = config.SaveGame(path, game.State())
+	}
+
+	if err != nil {
+		log.Println(err)
+	}
+}
+
+// loadThemes registers the theme files of the player, a broken one is
+// skipped.
+func loadThemes() {
+	dir, err := config.ThemesDir()
+	if err != nil {
+		log.Println(err)
+		return
+	}
+
+	paths, _ := filepath.Glob(filepath.Join(dir, "*.json"))
+	for _, path := range paths {
+		theme, err := gamePkg.LoadTheme(path)
+		if err != nil {
+			log.Println(err)
+			continue
+		}
+
+		gamePkg.RegisterTheme(theme)
+	}
+}
+
+func loadConfig() *config.Config {
+	path, err := config.DefaultPath()
+	if err == nil {
+		var settings *config.Config
+		settings, err = config.Load(path)
+		if err == nil {
+			return settings
+		}
+	}
+
+	log.Println(err)
+
+	return config.Default()
+}
+
+func loadHighScores() *highscore.Store {
+	path, err := highscore.DefaultPath()
+	if err == nil {
+		var scores *highscore.Store
+		scores, err = highscore.Load(path)
+		if err == nil {
+			return scores
+		}
+	}
+
+	log.Println(err)
+	scores, _ := highscore.Load("")
+
+	return scores
+}
+
+func spectate(address string) {
+	spectator, err := stream.Dial(address)
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer spectator.Close()
 
-			if gameStatus != gamePkg.Continue && gameStatus != gamePkg.Pause {
-				rl.EndDrawing()
-				break
+	state, err := spectator.Next()
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	var mutex sync.Mutex
+	go func() {
+		for {
+			next, err := spectator.Next()
+			if err != nil {
+				log.Println(err)
+				return
 			}
 
-			rl.EndDrawing()
+			mutex.Lock()
+			state = next
+			mutex.Unlock()
 		}
+	}()
+
+	game := newBoard(state.Grid)
+	game.Init()
+
+	for !rl.WindowShouldClose() {
+		mutex.Lock()
+		err := game.SetState(state)
+		mutex.Unlock()
 
-		switch gameStatus {
-		case gamePkg.Victory:
-			game.DisplayVictory()
-		case gamePkg.GameOver:
-			game.DisplayGameOver()
+		if err != nil {
+			log.Println(err)
+			break
 		}
 
+		rl.BeginDrawing()
+		game.Draw()
+		game.DisplaySpectating()
 		rl.EndDrawing()
 	}
 
 	rl.CloseWindow()
 }
+
+func newBoard(grid int32) *gamePkg.Board {
+	snakeSize := (gameSize - 2*border) / grid
+
+	position := gamePkg.NewCoordinateConverter(
+		20,
+		60,
+		snakeSize,
+	)
+	snake := gamePkg.NewSnake(grid, position)
+	snake.Init()
+
+	board := gamePkg.NewBoard(snake, gameSize, grid, 20, 0.2, position)
+	board.SetDifficulty(gamePkg.Normal)
+
+	return board
+}
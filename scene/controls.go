@@ -0,0 +1,126 @@
+package scene
+
+import (
+	"fmt"
+	"log"
+	"strings"
+
+	"github.com/blackprism/goti-snake/controls"
+	rl "github.com/gen2brain/raylib-go/raylib"
+)
+
+// customProfile names bindings changed by the player.
+const customProfile = "Custom"
+
+// bindings is the rebinding scene, the first row picks a built-in profile
+// and each following one rebinds an action.
+type bindings struct {
+	context   *Context
+	menu      menu
+	capturing bool
+}
+
+func (scene *bindings) Enter() {
+	scene.menu.selected = 0
+	scene.capturing = false
+}
+
+func (scene *bindings) Exit() {
+	if err := scene.context.Config.Save(); err != nil {
+		log.Println(err)
+	}
+}
+
+func (scene *bindings) Update() Scene {
+	config := scene.context.Config
+	input := scene.context.Input
+	actions := controls.Actions()
+
+	if scene.capturing {
+		key := input.GetKeyPressed()
+		if key == rl.KeyEscape {
+			scene.capturing = false
+		} else if key != 0 {
+			config.Keys.Bind(actions[scene.menu.selected-1], controls.Key(key))
+			config.Profile = customProfile
+			scene.capturing = false
+		}
+
+		return Controls
+	}
+
+	if scene.context.cancelled() {
+		return Settings
+	}
+
+	if scene.menu.selected == 0 {
+		if scene.context.navigated(controls.TurnLeft) {
+			scene.useProfile(-1)
+		}
+
+		if scene.context.navigated(controls.TurnRight) {
+			scene.useProfile(1)
+		}
+	}
+
+	action := scene.menu.selected - 1
+	if action >= 0 && action < len(actions) && input.IsKeyPressed(rl.KeyDelete) {
+		config.Keys.Clear(actions[action])
+		config.Profile = customProfile
+	}
+
+	if scene.menu.update(scene.context, len(actions)+2) {
+		switch {
+		case scene.menu.selected == 0:
+			scene.useProfile(1)
+		case scene.menu.selected > len(actions):
+			return Settings
+		default:
+			scene.capturing = true
+		}
+	}
+
+	return follow(scene.context.Board, Controls)
+}
+
+// useProfile replaces the bindings with the built-in profile step away
+// from the current one.
+func (scene *bindings) useProfile(step int) {
+	config := scene.context.Config
+	names := controls.ProfileNames()
+
+	next := 0
+	for index, name := range names {
+		if name == config.Profile {
+			next = (index + step + len(names)) % len(names)
+		}
+	}
+
+	config.Profile = names[next]
+	config.Keys, _ = controls.Profile(names[next])
+}
+
+func (scene *bindings) Draw() {
+	config := scene.context.Config
+
+	labels := []string{fmt.Sprintf("Profile: < %s >", config.Profile)}
+	for _, action := range controls.Actions() {
+		var keys []string
+		for _, key := range config.Keys[action] {
+			keys = append(keys, key.String())
+		}
+
+		labels = append(labels, fmt.Sprintf("%s: %s", action, strings.Join(keys, ", ")))
+	}
+	labels = append(labels, "Back")
+
+	rl.ClearBackground(scene.context.theme().Background)
+	rl.DrawText("Controls", scene.context.Width/2-100, 40, 40, scene.context.theme().Text)
+	scene.menu.draw(scene.context, labels, scene.context.Width/2-250, 110)
+
+	help := "[ENTER] add a key  [DELETE] clear  [ESC] back"
+	if scene.capturing {
+		help = "Press the new key, [ESC] to cancel"
+	}
+	rl.DrawText(help, scene.context.Width/2-250, scene.context.Height-60, 20, scene.context.faded())
+}
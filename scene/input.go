@@ -0,0 +1,110 @@
+package scene
+
+import (
+	"strings"
+
+	"github.com/blackprism/goti-snake/controls"
+	rl "github.com/gen2brain/raylib-go/raylib"
+)
+
+// Input is the source of key presses, GetKeyPressed returns 0 when no key
+// was pressed.
+type Input interface {
+	IsKeyPressed(key int32) bool
+	GetKeyPressed() int32
+}
+
+type Keyboard struct{}
+
+func (keyboard Keyboard) IsKeyPressed(key int32) bool {
+	return rl.IsKeyPressed(key)
+}
+
+func (keyboard Keyboard) GetKeyPressed() int32 {
+	return rl.GetKeyPressed()
+}
+
+// Window tells whether the player can see the game, it pauses when they
+// can't.
+type Window interface {
+	IsWindowFocused() bool
+	IsWindowMinimized() bool
+}
+
+type RaylibWindow struct{}
+
+func (window RaylibWindow) IsWindowFocused() bool {
+	return rl.IsWindowFocused()
+}
+
+func (window RaylibWindow) IsWindowMinimized() bool {
+	return rl.IsWindowMinimized()
+}
+
+// menuKeys always move in the menus, whatever the key bindings.
+var menuKeys = map[controls.Action]int32{
+	controls.TurnUp:    rl.KeyUp,
+	controls.TurnDown:  rl.KeyDown,
+	controls.TurnLeft:  rl.KeyLeft,
+	controls.TurnRight: rl.KeyRight,
+}
+
+// pressed tells whether a key bound to action, any gamepad or a swipe
+// triggered it.
+func (context *Context) pressed(action controls.Action) bool {
+	return context.Config.Keys.Pressed(context.Input, action) ||
+		context.Gamepads.Pressed(action) ||
+		context.Pointer.Pressed(action)
+}
+
+func (context *Context) confirmed() bool {
+	return confirmed(context.Input) || context.Gamepads.Confirmed()
+}
+
+func (context *Context) cancelled() bool {
+	return cancelled(context.Input) || context.Gamepads.Cancelled()
+}
+
+// away tells whether the window lost the focus or was minimised, never
+// without a window.
+func (context *Context) away() bool {
+	if context.Window == nil {
+		return false
+	}
+
+	return !context.Window.IsWindowFocused() || context.Window.IsWindowMinimized()
+}
+
+// navigated tells whether the player moved in a menu, with the arrows or
+// the keys bound to the turn.
+func (context *Context) navigated(action controls.Action) bool {
+	return context.Input.IsKeyPressed(menuKeys[action]) || context.pressed(action)
+}
+
+func pressedAny(input Input, keys ...int32) bool {
+	for _, key := range keys {
+		if input.IsKeyPressed(key) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func confirmed(input Input) bool {
+	return pressedAny(input, rl.KeyEnter, rl.KeyKpEnter)
+}
+
+func cancelled(input Input) bool {
+	return pressedAny(input, rl.KeyEscape, rl.KeyBackspace)
+}
+
+// keyName names the first key bound to action for the help texts.
+func (context *Context) keyName(action controls.Action) string {
+	keys := context.Config.Keys[action]
+	if len(keys) == 0 {
+		return "unbound"
+	}
+
+	return strings.ToUpper(keys[0].String())
+}
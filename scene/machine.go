@@ -0,0 +1,158 @@
+package scene
+
+import "fmt"
+
+type Scene int
+
+const (
+	Quit        Scene = -1
+	Title       Scene = 0
+	ModeSelect  Scene = 1
+	Playing     Scene = 2
+	Paused      Scene = 3
+	GameOver    Scene = 4
+	Victory     Scene = 5
+	HighScores  Scene = 6
+	Settings    Scene = 7
+	Controls    Scene = 8
+	Multiplayer Scene = 9
+	ConfirmQuit Scene = 10
+)
+
+var sceneNames = map[Scene]string{
+	Quit:        "quit",
+	Title:       "title",
+	ModeSelect:  "mode select",
+	Playing:     "playing",
+	Paused:      "paused",
+	GameOver:    "game over",
+	Victory:     "victory",
+	HighScores:  "high scores",
+	Settings:    "settings",
+	Controls:    "controls",
+	Multiplayer: "multiplayer",
+	ConfirmQuit: "confirm quit",
+}
+
+// Playing is reachable from every single player scene as a game can also
+// be started from the HTTP API whatever scene the window shows. Multiplayer
+// plays its own boards and only goes back to the title.
+var transitions = map[Scene][]Scene{
+	Title:       {ModeSelect, Multiplayer, HighScores, Settings, Playing},
+	ModeSelect:  {Title, Playing},
+	Playing:     {Paused, GameOver, Victory, ConfirmQuit},
+	Paused:      {Playing, GameOver, Victory, Settings, Title, ConfirmQuit},
+	GameOver:    {Playing, HighScores, Title},
+	Victory:     {Playing, HighScores, Title},
+	HighScores:  {Title, Playing},
+	Settings:    {Title, Paused, Controls, Playing, ConfirmQuit},
+	Controls:    {Settings, Playing, ConfirmQuit},
+	Multiplayer: {Title},
+	ConfirmQuit: {Paused, Playing},
+}
+
+// Handler is one scene, Update returns the scene to go to next, itself to
+// stay or Quit to end the machine.
+type Handler interface {
+	Enter()
+	Exit()
+	Update() Scene
+	Draw()
+}
+
+// closer is a scene with something to lose when the window is closed,
+// closing returns the scene asking the player first or Quit. Other scenes
+// quit right away.
+type closer interface {
+	closing() Scene
+}
+
+func NewMachine(handlers map[Scene]Handler, start Scene) *Machine {
+	machine := &Machine{
+		handlers: handlers,
+		current:  start,
+	}
+
+	handlers[start].Enter()
+
+	return machine
+}
+
+type Machine struct {
+	handlers map[Scene]Handler
+	current  Scene
+	done     bool
+}
+
+func (scene Scene) String() string {
+	return sceneNames[scene]
+}
+
+func CanGo(from Scene, to Scene) bool {
+	for _, allowed := range transitions[from] {
+		if allowed == to {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (machine *Machine) Current() Scene {
+	return machine.current
+}
+
+func (machine *Machine) Done() bool {
+	return machine.done
+}
+
+func (machine *Machine) Go(next Scene) error {
+	if next == Quit {
+		machine.done = true
+		return nil
+	}
+
+	if !CanGo(machine.current, next) {
+		return fmt.Errorf("no transition from %s to %s", machine.current, next)
+	}
+
+	handler, ok := machine.handlers[next]
+	if !ok {
+		return fmt.Errorf("no handler for %s", next)
+	}
+
+	machine.handlers[machine.current].Exit()
+	machine.current = next
+	handler.Enter()
+
+	return nil
+}
+
+// Close ends the machine when the window is closed, unless the current
+// scene asks the player first. Closing again while asked quits.
+func (machine *Machine) Close() error {
+	closer, ok := machine.handlers[machine.current].(closer)
+	if !ok {
+		return machine.Go(Quit)
+	}
+
+	next := closer.closing()
+	if next == machine.current {
+		return nil
+	}
+
+	return machine.Go(next)
+}
+
+func (machine *Machine) Update() error {
+	next := machine.handlers[machine.current].Update()
+	if next == machine.current {
+		return nil
+	}
+
+	return machine.Go(next)
+}
+
+func (machine *Machine) Draw() {
+	machine.handlers[machine.current].Draw()
+}
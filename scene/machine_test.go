@@ -0,0 +1,463 @@
+package scene
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/blackprism/goti-snake/config"
+	"github.com/blackprism/goti-snake/game"
+	"github.com/blackprism/goti-snake/highscore"
+	rl "github.com/gen2brain/raylib-go/raylib"
+)
+
+// recorder is a scene going to next on its update and logging its hooks.
+type recorder struct {
+	scene Scene
+	next  Scene
+	log   *[]string
+}
+
+func (recorder *recorder) Enter() {
+	*recorder.log = append(*recorder.log, "enter "+recorder.scene.String())
+}
+
+func (recorder *recorder) Exit() {
+	*recorder.log = append(*recorder.log, "exit "+recorder.scene.String())
+}
+
+func (recorder *recorder) Update() Scene {
+	return recorder.next
+}
+
+func (recorder *recorder) Draw() {}
+
+func recorders(log *[]string, scenes ...Scene) map[Scene]*recorder {
+	handlers := map[Scene]*recorder{}
+	for _, scene := range scenes {
+		handlers[scene] = &recorder{scene: scene, next: scene, log: log}
+	}
+
+	return handlers
+}
+
+func newRecordingMachine(log *[]string, start Scene, scenes ...Scene) (*Machine, map[Scene]*recorder) {
+	handlers := recorders(log, scenes...)
+
+	machine := map[Scene]Handler{}
+	for scene, handler := range handlers {
+		machine[scene] = handler
+	}
+
+	return NewMachine(machine, start), handlers
+}
+
+func TestMachineHooks(t *testing.T) {
+	var log []string
+	machine, handlers := newRecordingMachine(&log, Title, Title, Settings)
+
+	handlers[Title].next = Settings
+	if err := machine.Update(); err != nil {
+		t.Fatal(err)
+	}
+
+	if machine.Current() != Settings {
+		t.Fatalf("current = %s, want settings", machine.Current())
+	}
+
+	want := []string{"enter title", "exit title", "enter settings"}
+	if !reflect.DeepEqual(log, want) {
+		t.Fatalf("hooks = %v, want %v", log, want)
+	}
+}
+
+func TestMachineStaysWithoutHooks(t *testing.T) {
+	var log []string
+	machine, _ := newRecordingMachine(&log, Title, Title)
+
+	if err := machine.Update(); err != nil {
+		t.Fatal(err)
+	}
+
+	if len(log) != 1 {
+		t.Fatalf("staying in a scene ran hooks: %v", log)
+	}
+}
+
+func TestMachineRefusesTransition(t *testing.T) {
+	var log []string
+	machine, _ := newRecordingMachine(&log, Title, Title, GameOver)
+
+	if err := machine.Go(GameOver); err == nil {
+		t.Fatal("title should not go to game over")
+	}
+
+	if machine.Current() != Title || len(log) != 1 {
+		t.Fatalf("a refused transition changed the scene: %s %v", machine.Current(), log)
+	}
+}
+
+func TestMachineWithoutHandler(t *testing.T) {
+	var log []string
+	machine, _ := newRecordingMachine(&log, Title, Title)
+
+	if err := machine.Go(Settings); err == nil {
+		t.Fatal("going to a scene without handler should fail")
+	}
+}
+
+func TestMachineQuit(t *testing.T) {
+	var log []string
+	machine, handlers := newRecordingMachine(&log, Title, Title)
+
+	handlers[Title].next = Quit
+	if err := machine.Update(); err != nil {
+		t.Fatal(err)
+	}
+
+	if !machine.Done() {
+		t.Fatal("the machine should be done")
+	}
+}
+
+func TestTransitionsHaveNames(t *testing.T) {
+	for from, targets := range transitions {
+		if from.String() == "" {
+			t.Errorf("scene %d has no name", from)
+		}
+
+		for _, to := range targets {
+			if to.String() == "" {
+				t.Errorf("scene %d has no name", to)
+			}
+
+			if _, ok := transitions[to]; !ok {
+				t.Errorf("%s goes to %d which can't be left", from, to)
+			}
+		}
+	}
+}
+
+func TestPlayingReachableFromSinglePlayerScenes(t *testing.T) {
+	for from := range transitions {
+		if from == Playing || from == Multiplayer {
+			continue
+		}
+
+		if !CanGo(from, Playing) {
+			t.Errorf("%s can't go to playing", from)
+		}
+	}
+
+	if !reflect.DeepEqual(transitions[Multiplayer], []Scene{Title}) {
+		t.Errorf("multiplayer goes to %v, want title only", transitions[Multiplayer])
+	}
+}
+
+type fakeInput struct {
+	pressed map[int32]bool
+	key     int32
+}
+
+func (input *fakeInput) IsKeyPressed(key int32) bool {
+	return input.pressed[key]
+}
+
+func (input *fakeInput) GetKeyPressed() int32 {
+	return input.key
+}
+
+type fakeWindow struct {
+	focused   bool
+	minimized bool
+}
+
+func (window *fakeWindow) IsWindowFocused() bool {
+	return window.focused
+}
+
+func (window *fakeWindow) IsWindowMinimized() bool {
+	return window.minimized
+}
+
+func newBoard(grid int32) *game.Board {
+	position := game.NewCoordinateConverter(20, 60, 560/grid)
+	snake := game.NewSnake(grid, position)
+	snake.Init()
+
+	return game.NewBoard(snake, 600, grid, 20, 0.2, position)
+}
+
+type harness struct {
+	t       *testing.T
+	machine *Machine
+	context *Context
+	input   *fakeInput
+	window  *fakeWindow
+}
+
+func newHarness(t *testing.T) *harness {
+	scores, err := highscore.Load("")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	input := &fakeInput{pressed: map[int32]bool{}}
+	window := &fakeWindow{focused: true}
+	context := &Context{
+		Board:    newBoard(20),
+		Scores:   scores,
+		Config:   config.Default(),
+		NewBoard: newBoard,
+		Input:    input,
+		Window:   window,
+	}
+
+	return &harness{t: t, machine: New(context), context: context, input: input, window: window}
+}
+
+// press runs one frame with the keys pressed, none for a frame without
+// input.
+func (harness *harness) press(keys ...int32) {
+	harness.input.pressed = map[int32]bool{}
+	for _, key := range keys {
+		harness.input.pressed[key] = true
+	}
+
+	if err := harness.machine.Update(); err != nil {
+		harness.t.Fatal(err)
+	}
+}
+
+func (harness *harness) expect(scene Scene) {
+	harness.t.Helper()
+
+	if harness.machine.Current() != scene {
+		harness.t.Fatalf("current = %s, want %s", harness.machine.Current(), scene)
+	}
+}
+
+func TestTitleMenu(t *testing.T) {
+	harness := newHarness(t)
+	harness.expect(Title)
+
+	harness.press(rl.KeyDown)
+	harness.press(rl.KeyDown)
+	harness.press(rl.KeyDown)
+	harness.press(rl.KeyEnter)
+	harness.expect(Settings)
+
+	harness.press(rl.KeyEscape)
+	harness.expect(Title)
+
+	harness.press(rl.KeyUp)
+	harness.press(rl.KeyEnter)
+	harness.expect(Title)
+
+	if !harness.machine.Done() {
+		t.Fatal("Quit should end the machine")
+	}
+}
+
+func TestPlayAndPause(t *testing.T) {
+	harness := newHarness(t)
+
+	harness.press(rl.KeyEnter)
+	harness.expect(ModeSelect)
+
+	harness.press(rl.KeyEnter)
+	harness.expect(Playing)
+
+	if harness.context.Board.Status() != game.Continue {
+		t.Fatalf("status = %s, want continue", harness.context.Board.Status())
+	}
+
+	harness.press(rl.KeySpace)
+	harness.expect(Paused)
+
+	if harness.context.Board.Status() != game.Pause {
+		t.Fatalf("status = %s, want pause", harness.context.Board.Status())
+	}
+
+	// Restart from the pause menu.
+	harness.press(rl.KeyDown)
+	harness.press(rl.KeyEnter)
+	harness.expect(Playing)
+
+	harness.press(rl.KeySpace)
+	harness.press(rl.KeyUp)
+	harness.press(rl.KeyEnter)
+	harness.expect(Title)
+
+	if harness.context.Board.Running() {
+		t.Fatal("quitting to the title should stop the game")
+	}
+}
+
+func TestPauseMenuSettings(t *testing.T) {
+	harness := newHarness(t)
+
+	harness.press(rl.KeyEnter)
+	harness.press(rl.KeyEnter)
+	harness.press(rl.KeySpace)
+	harness.press(rl.KeyDown)
+	harness.press(rl.KeyDown)
+	harness.press(rl.KeyEnter)
+	harness.expect(Settings)
+
+	harness.press(rl.KeyEscape)
+	harness.expect(Paused)
+}
+
+func TestPausesAway(t *testing.T) {
+	harness := newHarness(t)
+
+	harness.press(rl.KeyEnter)
+	harness.press(rl.KeyEnter)
+	harness.expect(Playing)
+
+	harness.window.focused = false
+	harness.press()
+	harness.expect(Paused)
+}
+
+func TestFollowsTheBoard(t *testing.T) {
+	harness := newHarness(t)
+
+	// A game started through the HTTP API.
+	harness.context.Board.Start()
+	harness.press()
+	harness.expect(Playing)
+
+	harness.context.Board.SetPaused(true)
+	harness.press()
+	harness.expect(Paused)
+
+	harness.context.Board.SetPaused(false)
+	harness.press()
+	harness.expect(Playing)
+}
+
+func TestCloseAsksDuringAGame(t *testing.T) {
+	harness := newHarness(t)
+
+	if err := harness.machine.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	if !harness.machine.Done() {
+		t.Fatal("closing without a game should quit right away")
+	}
+
+	harness = newHarness(t)
+	harness.press(rl.KeyEnter)
+	harness.press(rl.KeyEnter)
+
+	if err := harness.machine.Close(); err != nil {
+		t.Fatal(err)
+	}
+	harness.expect(ConfirmQuit)
+
+	harness.press(rl.KeyDown)
+	harness.press(rl.KeyEnter)
+	harness.expect(Paused)
+
+	if err := harness.machine.Close(); err != nil {
+		t.Fatal(err)
+	}
+	harness.expect(ConfirmQuit)
+
+	if err := harness.machine.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	if !harness.machine.Done() {
+		t.Fatal("closing again while asked should quit")
+	}
+}
+
+func TestCloseAsksDuringAMatch(t *testing.T) {
+	harness := newHarness(t)
+	harness.press(rl.KeyDown)
+	harness.press(rl.KeyEnter)
+	harness.expect(Multiplayer)
+
+	if err := harness.machine.Close(); err != nil {
+		t.Fatal(err)
+	}
+	harness.expect(Multiplayer)
+
+	if harness.machine.Done() {
+		t.Fatal("closing during a match should ask first")
+	}
+
+	// Keep playing, the match resumes once unpaused.
+	harness.press(rl.KeyDown)
+	harness.press(rl.KeyEnter)
+	harness.press(rl.KeySpace)
+	harness.press()
+	harness.expect(Multiplayer)
+
+	match := harness.machine.handlers[Multiplayer].(*multiplayer)
+	if match.quitting || match.players[0].board.Status() != game.Continue {
+		t.Fatalf("the match should go on, player one is %s", match.players[0].board.Status())
+	}
+
+	if err := harness.machine.Close(); err != nil {
+		t.Fatal(err)
+	}
+	harness.press(rl.KeyEnter)
+
+	if !harness.machine.Done() {
+		t.Fatal("choosing to quit the match should quit")
+	}
+}
+
+func TestQuittingZenKeepsTheScore(t *testing.T) {
+	harness := newHarness(t)
+
+	harness.press(rl.KeyEnter)
+	harness.press(rl.KeyUp)
+	harness.press(rl.KeyEnter)
+	harness.expect(Playing)
+
+	if harness.context.Board.Mode() != game.Zen {
+		t.Fatalf("mode = %s, want zen", harness.context.Board.Mode())
+	}
+
+	harness.press(rl.KeySpace)
+	harness.press(rl.KeyUp)
+	harness.press(rl.KeyEnter)
+	harness.expect(Title)
+
+	if entries := harness.context.Scores.Table(game.Zen.String()); len(entries) != 1 {
+		t.Fatalf("%d zen scores, quitting should record one", len(entries))
+	}
+}
+
+func TestEscapeAsksDuringAGame(t *testing.T) {
+	harness := newHarness(t)
+	harness.press(rl.KeyEnter)
+	harness.press(rl.KeyEnter)
+	harness.expect(Playing)
+
+	harness.press(rl.KeyEscape)
+	harness.expect(ConfirmQuit)
+
+	if harness.context.Board.Status() != game.Pause {
+		t.Fatal("the game should wait while the player is asked")
+	}
+
+	harness.press(rl.KeyEscape)
+	harness.expect(Paused)
+
+	// Back to the game once the countdown is over.
+	harness.context.Board.SetPaused(false)
+	harness.press()
+	harness.expect(Playing)
+
+	harness.press(rl.KeyEscape)
+	harness.press(rl.KeyEnter)
+	if !harness.machine.Done() {
+		t.Fatal("saving and quitting should end the machine")
+	}
+}
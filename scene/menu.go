@@ -0,0 +1,57 @@
+package scene
+
+import (
+	"github.com/blackprism/goti-snake/audio"
+	"github.com/blackprism/goti-snake/controls"
+	"github.com/blackprism/goti-snake/game"
+	rl "github.com/gen2brain/raylib-go/raylib"
+)
+
+type menu struct {
+	selected int
+}
+
+// update moves the selection among count items and tells whether the
+// selected one was chosen.
+func (menu *menu) update(context *Context, count int) bool {
+	if context.navigated(controls.TurnUp) {
+		menu.selected = (menu.selected + count - 1) % count
+		context.Audio.Play(audio.Blip)
+	}
+
+	if context.navigated(controls.TurnDown) {
+		menu.selected = (menu.selected + 1) % count
+		context.Audio.Play(audio.Blip)
+	}
+
+	if context.confirmed() {
+		context.Audio.Play(audio.Blip)
+		return true
+	}
+
+	return false
+}
+
+func (menu *menu) draw(context *Context, labels []string, x int32, y int32) {
+	for index, label := range labels {
+		color := context.faded()
+		if index == menu.selected {
+			color = context.theme().Text
+			label = "> " + label
+		}
+
+		rl.DrawText(label, x, y+int32(index)*32, 24, color)
+	}
+}
+
+func (context *Context) theme() game.Theme {
+	return context.Board.Theme()
+}
+
+// faded is the theme text color for hints and unselected items.
+func (context *Context) faded() rl.Color {
+	color := context.theme().Text
+	color.A = 150
+
+	return color
+}
@@ -0,0 +1,51 @@
+package scene
+
+import (
+	"github.com/blackprism/goti-snake/game"
+	rl "github.com/gen2brain/raylib-go/raylib"
+)
+
+// miniature renders a board with the real renderer in a texture drawn
+// scaled down, for the settings preview or the boards of several players.
+type miniature struct {
+	board   *game.Board
+	texture rl.RenderTexture2D
+	width   int32
+	height  int32
+}
+
+func newMiniature(context *Context, board *game.Board) *miniature {
+	return &miniature{
+		board:   board,
+		texture: rl.LoadRenderTexture(context.Width, context.Height),
+		width:   context.Width,
+		height:  context.Height,
+	}
+}
+
+// draw renders the board width pixels wide at x, y and returns the height
+// drawn.
+func (miniature *miniature) draw(x int32, y int32, width int32) int32 {
+	rl.BeginTextureMode(miniature.texture)
+	miniature.board.Draw()
+	rl.EndTextureMode()
+
+	height := width * miniature.height / miniature.width
+
+	// Render textures are stored upside down, hence the negative height.
+	rl.DrawTexturePro(
+		miniature.texture.Texture,
+		rl.NewRectangle(0, 0, float32(miniature.width), -float32(miniature.height)),
+		rl.NewRectangle(float32(x), float32(y), float32(width), float32(height)),
+		rl.NewVector2(0, 0),
+		0,
+		rl.White,
+	)
+	rl.DrawRectangleLines(x, y, width, height, rl.Gray)
+
+	return height
+}
+
+func (miniature *miniature) unload() {
+	rl.UnloadRenderTexture(miniature.texture)
+}
@@ -0,0 +1,225 @@
+package scene
+
+import (
+	"fmt"
+
+	"github.com/blackprism/goti-snake/controls"
+	"github.com/blackprism/goti-snake/game"
+	rl "github.com/gen2brain/raylib-go/raylib"
+)
+
+// multiplayer gives each player a board of their own side by side, the
+// keyboard steers the first one and each connected gamepad another one.
+type multiplayer struct {
+	context  *Context
+	players  []*player
+	paused   bool
+	quitting bool
+	menu     menu
+}
+
+var quitMatchItems = []string{"Quit", "Keep playing"}
+
+type player struct {
+	gamepad *controls.Gamepad
+	board   *game.Board
+	screen  *miniature
+}
+
+// pressed tells whether the player triggered action, with the keyboard for
+// the first player and their gamepad for the others.
+func (player *player) pressed(context *Context, action controls.Action) bool {
+	if player.gamepad == nil {
+		return context.Config.Keys.Pressed(context.Input, action)
+	}
+
+	return player.gamepad.Pressed(action)
+}
+
+func (scene *multiplayer) Enter() {
+	scene.players = []*player{{}}
+	for _, gamepad := range scene.context.Gamepads {
+		if gamepad.Available() {
+			scene.players = append(scene.players, &player{gamepad: gamepad})
+		}
+	}
+
+	for _, player := range scene.players {
+		player.board = scene.context.NewBoard(scene.context.Config.Grid)
+		scene.context.Config.Apply(player.board)
+		player.board.SetMode(scene.context.Board.Mode())
+	}
+
+	scene.start()
+}
+
+func (scene *multiplayer) Exit() {
+	for _, player := range scene.players {
+		if player.screen != nil {
+			player.screen.unload()
+		}
+	}
+
+	scene.players = nil
+}
+
+func (scene *multiplayer) start() {
+	scene.paused = false
+	scene.quitting = false
+	for _, player := range scene.players {
+		player.board.Start()
+	}
+}
+
+func (scene *multiplayer) Update() Scene {
+	context := scene.context
+
+	if scene.quitting {
+		return scene.confirmQuit()
+	}
+
+	if cancelled(context.Input) {
+		return Title
+	}
+
+	for _, player := range scene.players {
+		if player.pressed(context, controls.Pause) {
+			scene.paused = !scene.paused
+			break
+		}
+	}
+
+	if context.away() {
+		scene.paused = true
+	}
+
+	if scene.over() {
+		for _, player := range scene.players {
+			if player.pressed(context, controls.NewGame) {
+				scene.start()
+				break
+			}
+		}
+
+		return Multiplayer
+	}
+
+	for _, player := range scene.players {
+		if player.board.Running() {
+			player.board.SetPaused(scene.paused)
+		}
+
+		if !scene.paused {
+			steer(player.board, context.Config.Steering, func(action controls.Action) bool {
+				return player.pressed(context, action)
+			})
+		}
+
+		player.board.Tick()
+	}
+
+	return Multiplayer
+}
+
+// closing asks before closing the window over a match in progress, the
+// boards stand still meanwhile. A match can't be saved.
+func (scene *multiplayer) closing() Scene {
+	if scene.quitting || scene.over() {
+		return Quit
+	}
+
+	scene.quitting = true
+	scene.paused = true
+	scene.menu.selected = 0
+
+	return Multiplayer
+}
+
+func (scene *multiplayer) confirmQuit() Scene {
+	if scene.context.cancelled() {
+		scene.quitting = false
+		return Multiplayer
+	}
+
+	if scene.menu.update(scene.context, len(quitMatchItems)) {
+		if scene.menu.selected == 0 {
+			return Quit
+		}
+
+		scene.quitting = false
+	}
+
+	return Multiplayer
+}
+
+// over tells whether every player lost or won.
+func (scene *multiplayer) over() bool {
+	for _, player := range scene.players {
+		if player.board.Running() {
+			return false
+		}
+	}
+
+	return true
+}
+
+// winner returns the player number with the best score starting at 1.
+func (scene *multiplayer) winner() int {
+	best := 0
+	for index, player := range scene.players {
+		if player.board.Score() > scene.players[best].board.Score() {
+			best = index
+		}
+	}
+
+	return best + 1
+}
+
+func (scene *multiplayer) Draw() {
+	rl.ClearBackground(scene.context.theme().Background)
+
+	columns := int32(1)
+	if len(scene.players) > 1 {
+		columns = 2
+	}
+	rows := (int32(len(scene.players)) + columns - 1) / columns
+
+	top := int32(40)
+	width := scene.context.Width/columns - 20
+	if height := (scene.context.Height - top - 10) / rows; width*scene.context.Height/scene.context.Width > height-24 {
+		width = (height - 24) * scene.context.Width / scene.context.Height
+	}
+
+	for index, player := range scene.players {
+		if player.screen == nil {
+			player.screen = newMiniature(scene.context, player.board)
+		}
+
+		x := 10 + int32(index)%columns*(scene.context.Width/columns)
+		y := top + int32(index)/columns*(width*scene.context.Height/scene.context.Width+24)
+
+		label := fmt.Sprintf("P%d  %d", index+1, player.board.Score())
+		if player.board.Status() == game.GameOver {
+			label += "  out"
+		}
+		rl.DrawText(label, x, y, 20, scene.context.theme().Text)
+		player.screen.draw(x, y+22, width)
+	}
+
+	help := "[ESC] quit to title"
+	if scene.over() {
+		help = fmt.Sprintf("P%d wins !  [%s] play again  [ESC] quit to title", scene.winner(), scene.context.keyName(controls.NewGame))
+	} else if scene.paused {
+		help = "Pause  " + help
+	} else if len(scene.players) == 1 {
+		help = "Connect gamepads to add players  " + help
+	}
+	rl.DrawText(help, 10, 10, 20, scene.context.theme().Text)
+
+	if scene.quitting {
+		context := scene.context
+		rl.DrawRectangle(0, 0, context.Width, context.Height, rl.NewColor(0, 0, 0, 160))
+		rl.DrawText("Quit the match?", context.Width/2-rl.MeasureText("Quit the match?", 40)/2, context.Height/2-90, 40, rl.White)
+		scene.menu.draw(context, quitMatchItems, context.Width/2-110, context.Height/2-30)
+	}
+}
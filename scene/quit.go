@@ -0,0 +1,79 @@
+package scene
+
+import (
+	"github.com/blackprism/goti-snake/game"
+	rl "github.com/gen2brain/raylib-go/raylib"
+)
+
+var confirmQuitItems = []string{"Save and quit", "Keep playing"}
+
+// confirmQuit asks before closing the window over a game in progress, the
+// game is saved to be resumed on the next launch.
+type confirmQuit struct {
+	context *Context
+	menu    menu
+}
+
+func (scene *confirmQuit) Enter() {
+	scene.menu.selected = 0
+
+	if scene.context.Board.Status() == game.Continue {
+		scene.context.Board.SetPaused(true)
+	}
+}
+
+func (scene *confirmQuit) Exit() {}
+
+func (scene *confirmQuit) Update() Scene {
+	if scene.context.cancelled() {
+		return Paused
+	}
+
+	if scene.menu.update(scene.context, len(confirmQuitItems)) {
+		if scene.menu.selected == 0 {
+			return Quit
+		}
+
+		return Paused
+	}
+
+	return follow(scene.context.Board, ConfirmQuit)
+}
+
+// closing asks before quitting a game in progress, from any scene of a
+// single player game.
+func (context *Context) closing() Scene {
+	if context.Board.Running() {
+		return ConfirmQuit
+	}
+
+	return Quit
+}
+
+func (scene *playing) closing() Scene {
+	return scene.context.closing()
+}
+
+func (scene *paused) closing() Scene {
+	return scene.context.closing()
+}
+
+func (scene *settings) closing() Scene {
+	return scene.context.closing()
+}
+
+func (scene *bindings) closing() Scene {
+	return scene.context.closing()
+}
+
+func (scene *confirmQuit) Draw() {
+	context := scene.context
+
+	context.Board.Draw()
+	rl.DrawRectangle(0, 0, context.Width, context.Height, rl.NewColor(0, 0, 0, 160))
+
+	rl.DrawText("Are you sure?", context.Width/2-rl.MeasureText("Are you sure?", 40)/2, context.Height/2-140, 40, rl.White)
+	hint := "The game in progress will be resumed next time."
+	rl.DrawText(hint, context.Width/2-rl.MeasureText(hint, 20)/2, context.Height/2-90, 20, rl.LightGray)
+	scene.menu.draw(context, confirmQuitItems, context.Width/2-110, context.Height/2-30)
+}
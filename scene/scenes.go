@@ -0,0 +1,683 @@
+package scene
+
+import (
+	"fmt"
+	"log"
+	"math"
+	"strconv"
+	"time"
+
+	"github.com/blackprism/goti-snake/audio"
+	"github.com/blackprism/goti-snake/config"
+	"github.com/blackprism/goti-snake/controls"
+	"github.com/blackprism/goti-snake/game"
+	"github.com/blackprism/goti-snake/highscore"
+	rl "github.com/gen2brain/raylib-go/raylib"
+)
+
+type Context struct {
+	Board    *game.Board
+	Scores   *highscore.Store
+	Config   *config.Config
+	NewBoard func(grid int32) *game.Board
+	Input    Input
+	Window   Window
+	Gamepads controls.Gamepads
+	Pointer  *controls.Pointer
+	Audio    *audio.Player
+	Width    int32
+	Height   int32
+}
+
+// New starts on the title, or on the pause menu over a resumed game.
+func New(context *Context) *Machine {
+	start := Title
+	if context.Board.Status() == game.Pause {
+		start = Paused
+	}
+
+	return NewMachine(map[Scene]Handler{
+		Title:       &title{context: context},
+		ModeSelect:  &modeSelect{context: context},
+		Playing:     &playing{context: context},
+		Paused:      &paused{context: context},
+		GameOver:    &ended{context: context, scene: GameOver},
+		Victory:     &ended{context: context, scene: Victory},
+		HighScores:  &highScores{context: context},
+		Settings:    &settings{context: context},
+		Controls:    &bindings{context: context},
+		Multiplayer: &multiplayer{context: context},
+		ConfirmQuit: &confirmQuit{context: context},
+	}, start)
+}
+
+// follow returns the scene matching a board status changed from outside
+// the window, through the HTTP API for instance.
+func follow(board *game.Board, current Scene) Scene {
+	switch board.Status() {
+	case game.Continue:
+		return Playing
+	case game.Pause:
+		if current == Playing {
+			return Paused
+		}
+	case game.GameOver:
+		if current == Playing || current == Paused {
+			return GameOver
+		}
+	case game.Victory:
+		if current == Playing || current == Paused {
+			return Victory
+		}
+	}
+
+	return current
+}
+
+type title struct {
+	context *Context
+	menu    menu
+}
+
+var titleItems = []string{"Play", "Local multiplayer", "High scores", "Settings", "Quit"}
+
+func (scene *title) Enter() {
+	scene.menu.selected = 0
+}
+
+func (scene *title) Exit() {}
+
+func (scene *title) Update() Scene {
+	if scene.menu.update(scene.context, len(titleItems)) {
+		return []Scene{ModeSelect, Multiplayer, HighScores, Settings, Quit}[scene.menu.selected]
+	}
+
+	return follow(scene.context.Board, Title)
+}
+
+func (scene *title) Draw() {
+	rl.ClearBackground(scene.context.theme().Background)
+	rl.DrawText("Goti Snake", scene.context.Width/2-130, 120, 50, scene.context.theme().SnakeHead)
+	scene.menu.draw(scene.context, titleItems, scene.context.Width/2-100, 260)
+}
+
+type modeSelect struct {
+	context *Context
+	menu    menu
+}
+
+func (scene *modeSelect) Enter() {
+	scene.menu.selected = int(scene.context.Board.Mode())
+}
+
+func (scene *modeSelect) Exit() {
+	if err := scene.context.Config.Save(); err != nil {
+		log.Println(err)
+	}
+}
+
+func (scene *modeSelect) Update() Scene {
+	board := scene.context.Board
+	modes := game.GameModes()
+
+	config := scene.context.Config
+
+	if scene.context.navigated(controls.TurnLeft) {
+		config.Difficulty = config.Difficulty.Previous()
+		board.SetDifficulty(config.Difficulty)
+	}
+
+	if scene.context.navigated(controls.TurnRight) {
+		config.Difficulty = config.Difficulty.Next()
+		board.SetDifficulty(config.Difficulty)
+	}
+
+	if scene.context.cancelled() {
+		return Title
+	}
+
+	if scene.menu.update(scene.context, len(modes)) {
+		board.SetMode(modes[scene.menu.selected])
+		board.Start()
+		return Playing
+	}
+
+	return follow(board, ModeSelect)
+}
+
+func (scene *modeSelect) Draw() {
+	var labels []string
+	for _, mode := range game.GameModes() {
+		labels = append(labels, mode.String())
+	}
+
+	rl.ClearBackground(scene.context.theme().Background)
+	rl.DrawText("Choose a mode", scene.context.Width/2-150, 80, 40, scene.context.theme().Text)
+	scene.menu.draw(scene.context, labels, scene.context.Width/2-100, 180)
+	rl.DrawText(fmt.Sprintf("Difficulty [LEFT/RIGHT]: %s", scene.context.Board.Difficulty()), scene.context.Width/2-200, 360, 20, scene.context.theme().Text)
+	rl.DrawText("[ENTER] play  [ESC] back", scene.context.Width/2-200, 400, 20, scene.context.faded())
+}
+
+type playing struct {
+	context *Context
+}
+
+var turns = []struct {
+	action    controls.Action
+	direction game.Direction
+}{
+	{action: controls.TurnRight, direction: game.Right},
+	{action: controls.TurnLeft, direction: game.Left},
+	{action: controls.TurnUp, direction: game.Up},
+	{action: controls.TurnDown, direction: game.Down},
+}
+
+// steer turns board with the turn actions pressed, toward a side of the
+// board or, with the relative steering, to the left or right of the snake.
+func steer(board *game.Board, steering controls.Steering, pressed func(action controls.Action) bool) {
+	if steering == controls.Relative {
+		if pressed(controls.TurnLeft) {
+			board.Steer(false)
+		}
+
+		if pressed(controls.TurnRight) {
+			board.Steer(true)
+		}
+
+		return
+	}
+
+	for _, turn := range turns {
+		if pressed(turn.action) && !board.Turn(turn.direction) {
+			break
+		}
+	}
+}
+
+// Enter resumes a paused game and starts a new one otherwise, a game
+// started from the HTTP API or the mode menu is already running.
+func (scene *playing) Enter() {
+	board := scene.context.Board
+
+	if board.Status() == game.Pause {
+		board.SetPaused(false)
+		return
+	}
+
+	if !board.Running() {
+		board.Start()
+	}
+}
+
+func (scene *playing) Exit() {}
+
+func (scene *playing) Update() Scene {
+	board := scene.context.Board
+	context := scene.context
+
+	if context.pressed(controls.NewGame) {
+		board.Start()
+	}
+
+	if context.pressed(controls.Pause) || context.away() {
+		board.SetPaused(true)
+		return Paused
+	}
+
+	// Escape asks before leaving the game like closing the window does.
+	if context.cancelled() {
+		return scene.closing()
+	}
+
+	if context.pressed(controls.Screenshot) {
+		rl.TakeScreenshot(fmt.Sprintf("goti-snake-%s.png", time.Now().Format("20060102-150405")))
+	}
+
+	if context.pressed(controls.ToggleAutopilot) {
+		board.SetAutopilot(!board.Autopilot())
+	}
+
+	if context.pressed(controls.ToggleDebug) {
+		board.SetDebug(!board.Debug())
+	}
+
+	steer(board, context.Config.Steering, context.pressed)
+
+	if x, y, tapped := context.Pointer.Tapped(); tapped {
+		board.TurnToward(x, y)
+	}
+
+	board.Tick()
+
+	return follow(board, Playing)
+}
+
+func (scene *playing) Draw() {
+	scene.context.Board.Draw()
+}
+
+// resumeSeconds is the countdown before the game goes on after a pause.
+const resumeSeconds = 3
+
+var pauseItems = []string{"Resume", "Restart", "Settings", "Quit to title"}
+
+// paused dims the board under a menu, resuming counts down first so that
+// the player has the time to get ready.
+type paused struct {
+	context   *Context
+	menu      menu
+	countdown float32
+}
+
+func (scene *paused) Enter() {
+	scene.menu.selected = 0
+	scene.countdown = 0
+
+	if scene.context.Board.Status() == game.Continue {
+		scene.context.Board.SetPaused(true)
+	}
+}
+
+func (scene *paused) Exit() {}
+
+func (scene *paused) Update() Scene {
+	board := scene.context.Board
+
+	if scene.countdown > 0 {
+		if scene.context.pressed(controls.Pause) || scene.context.cancelled() || scene.context.away() {
+			scene.countdown = 0
+			return Paused
+		}
+
+		scene.countdown -= rl.GetFrameTime()
+		if scene.countdown <= 0 {
+			return Playing
+		}
+
+		return follow(board, Paused)
+	}
+
+	if scene.context.pressed(controls.Pause) || scene.context.cancelled() {
+		scene.countdown = resumeSeconds
+		return Paused
+	}
+
+	if scene.menu.update(scene.context, len(pauseItems)) {
+		switch scene.menu.selected {
+		case 0:
+			scene.countdown = resumeSeconds
+		case 1:
+			scene.endEndless()
+			board.Start()
+			return Playing
+		case 2:
+			return Settings
+		case 3:
+			scene.endEndless()
+			board.Stop()
+			return Title
+		}
+	}
+
+	return follow(board, Paused)
+}
+
+// endEndless keeps the score of a game which only ends when the player
+// leaves it, such as zen.
+func (scene *paused) endEndless() {
+	if scene.context.Board.Endless() {
+		scene.context.recordScore()
+	}
+}
+
+func (scene *paused) Draw() {
+	context := scene.context
+
+	context.Board.Draw()
+	rl.DrawRectangle(0, 0, context.Width, context.Height, rl.NewColor(0, 0, 0, 160))
+
+	if scene.countdown > 0 {
+		text := strconv.Itoa(int(math.Ceil(float64(scene.countdown))))
+		rl.DrawText(text, context.Width/2-rl.MeasureText(text, 120)/2, context.Height/2-60, 120, rl.White)
+		return
+	}
+
+	rl.DrawText("Pause", context.Width/2-rl.MeasureText("Pause", 40)/2, context.Height/2-120, 40, rl.White)
+	scene.menu.draw(context, pauseItems, context.Width/2-90, context.Height/2-50)
+}
+
+// ended is both the game over and the victory scene, entering it records
+// the score in the high scores of the mode played.
+type ended struct {
+	context *Context
+	scene   Scene
+	rank    int
+}
+
+func (scene *ended) Enter() {
+	scene.rank = scene.context.recordScore()
+}
+
+// recordScore adds the score of the board to the high scores of its mode
+// and returns its rank.
+func (context *Context) recordScore() int {
+	board := context.Board
+
+	rank := context.Scores.Add(board.Mode().String(), board.Score(), time.Now())
+	if err := context.Scores.Save(); err != nil {
+		log.Println(err)
+	}
+
+	return rank
+}
+
+func (scene *ended) Exit() {}
+
+func (scene *ended) Update() Scene {
+	input := scene.context.Input
+
+	if scene.context.pressed(controls.NewGame) || scene.context.confirmed() {
+		return Playing
+	}
+
+	if input.IsKeyPressed(rl.KeyH) {
+		return HighScores
+	}
+
+	if scene.context.cancelled() {
+		return Title
+	}
+
+	return follow(scene.context.Board, scene.scene)
+}
+
+func (scene *ended) Draw() {
+	board := scene.context.Board
+
+	board.Draw()
+	if scene.scene == Victory {
+		board.DisplayVictory()
+	} else {
+		board.DisplayGameOver()
+	}
+
+	if scene.rank > 0 {
+		rl.DrawText(fmt.Sprintf("New high score, rank %d !", scene.rank), scene.context.Width/2-270, 140, 20, scene.context.theme().SnakeHead)
+	}
+
+	rl.DrawText("[ENTER] play again  [H] high scores  [ESC] title", scene.context.Width/2-270, 80, 20, scene.context.theme().Text)
+}
+
+type highScores struct {
+	context *Context
+	mode    game.GameMode
+}
+
+func (scene *highScores) Enter() {
+	scene.mode = scene.context.Board.Mode()
+}
+
+func (scene *highScores) Exit() {}
+
+func (scene *highScores) Update() Scene {
+	if scene.context.navigated(controls.TurnLeft) {
+		scene.mode = scene.mode.Previous()
+	}
+
+	if scene.context.navigated(controls.TurnRight) {
+		scene.mode = scene.mode.Next()
+	}
+
+	if scene.context.cancelled() || scene.context.confirmed() {
+		return Title
+	}
+
+	return follow(scene.context.Board, HighScores)
+}
+
+func (scene *highScores) Draw() {
+	x := scene.context.Width/2 - 200
+
+	rl.ClearBackground(scene.context.theme().Background)
+	rl.DrawText("High scores", x, 60, 40, scene.context.theme().Text)
+	rl.DrawText(fmt.Sprintf("< %s >", scene.mode), x, 120, 24, scene.context.theme().Text)
+
+	entries := scene.context.Scores.Table(scene.mode.String())
+	if len(entries) == 0 {
+		rl.DrawText("No score yet", x, 170, 20, scene.context.faded())
+	}
+
+	for index, entry := range entries {
+		rl.DrawText(
+			fmt.Sprintf("%2d. %6d   %s", index+1, entry.Score, entry.Date.Format("2006-01-02")),
+			x,
+			170+int32(index)*28,
+			20,
+			scene.context.theme().Text,
+		)
+	}
+
+	rl.DrawText("[LEFT/RIGHT] mode  [ESC] back", x, scene.context.Height-60, 20, scene.context.faded())
+}
+
+type settings struct {
+	context *Context
+	menu    menu
+	preview *miniature
+}
+
+// setting is a line of the settings, change moves its value by step being
+// 1 or -1.
+type setting struct {
+	label  func(config *config.Config) string
+	change func(config *config.Config, step int)
+}
+
+var settingItems = []setting{
+	{
+		label: func(config *config.Config) string { return fmt.Sprintf("Grid size: < %d >", config.Grid) },
+		change: func(config *config.Config, step int) {
+			config.Grid = config.NextGrid(step)
+		},
+	},
+	{
+		label: func(config *config.Config) string { return fmt.Sprintf("Speed: < %s >", config.Difficulty) },
+		change: func(config *config.Config, step int) {
+			if step > 0 {
+				config.Difficulty = config.Difficulty.Next()
+			} else {
+				config.Difficulty = config.Difficulty.Previous()
+			}
+		},
+	},
+	{
+		label: func(config *config.Config) string { return fmt.Sprintf("Walls: < %s >", config.Walls) },
+		change: func(config *config.Config, step int) {
+			config.Walls = config.Walls.Next()
+		},
+	},
+	{
+		label: func(config *config.Config) string { return fmt.Sprintf("Theme: < %s >", config.Theme) },
+		change: func(config *config.Config, step int) {
+			config.Theme = config.NextTheme(step)
+		},
+	},
+	{
+		label: func(config *config.Config) string { return fmt.Sprintf("Shape cues: %s", onOff(config.ShapeCues)) },
+		change: func(config *config.Config, step int) {
+			config.ShapeCues = !config.ShapeCues
+		},
+	},
+	{
+		label: func(config *config.Config) string { return fmt.Sprintf("Sprites: %s", onOff(config.Sprites)) },
+		change: func(config *config.Config, step int) {
+			config.Sprites = !config.Sprites
+		},
+	},
+	{
+		label: func(config *config.Config) string { return fmt.Sprintf("Smooth movement: %s", onOff(config.Smooth)) },
+		change: func(config *config.Config, step int) {
+			config.Smooth = !config.Smooth
+		},
+	},
+	{
+		label: func(config *config.Config) string { return fmt.Sprintf("Effects: %s", onOff(config.Effects)) },
+		change: func(config *config.Config, step int) {
+			config.Effects = !config.Effects
+		},
+	},
+	{
+		label: func(config *config.Config) string { return fmt.Sprintf("Tail cut: %s", onOff(config.TailCut)) },
+		change: func(config *config.Config, step int) {
+			config.TailCut = !config.TailCut
+		},
+	},
+	{
+		label: func(config *config.Config) string {
+			return fmt.Sprintf("Drop cut tail as food: %s", onOff(config.DropCutTail))
+		},
+		change: func(config *config.Config, step int) {
+			config.DropCutTail = !config.DropCutTail
+		},
+	},
+	{
+		label: func(config *config.Config) string { return fmt.Sprintf("Grow at tail: %s", onOff(config.GrowAtTail)) },
+		change: func(config *config.Config, step int) {
+			config.GrowAtTail = !config.GrowAtTail
+		},
+	},
+	{
+		label: func(config *config.Config) string {
+			return fmt.Sprintf("Volume: < %d%% >", int(math.Round(float64(config.Volume*100))))
+		},
+		change: func(config *config.Config, step int) {
+			config.Volume = float32(math.Round(float64(config.Volume*10)+float64(step))) / 10
+			config.Volume = float32(math.Max(0, math.Min(1, float64(config.Volume))))
+		},
+	},
+	{
+		label: func(config *config.Config) string { return fmt.Sprintf("Music: %s", onOff(config.Music)) },
+		change: func(config *config.Config, step int) {
+			config.Music = !config.Music
+		},
+	},
+	{
+		label: func(config *config.Config) string { return fmt.Sprintf("Steering: < %s >", config.Steering) },
+		change: func(config *config.Config, step int) {
+			config.Steering = config.Steering.Next()
+		},
+	},
+}
+
+func (scene *settings) Enter() {
+	scene.menu.selected = 0
+}
+
+// Exit saves the settings, the board already got them while they changed.
+func (scene *settings) Exit() {
+	if err := scene.context.Config.Save(); err != nil {
+		log.Println(err)
+	}
+
+	if scene.preview != nil {
+		scene.preview.unload()
+		scene.preview = nil
+	}
+}
+
+// Update changes the selected setting with left and right, the two last
+// items open the controls and go back.
+func (scene *settings) Update() Scene {
+	if scene.context.cancelled() {
+		return scene.back()
+	}
+
+	step := 0
+	if scene.context.navigated(controls.TurnLeft) {
+		step = -1
+	}
+
+	if scene.context.navigated(controls.TurnRight) {
+		step = 1
+	}
+
+	if scene.menu.update(scene.context, len(settingItems)+2) {
+		switch scene.menu.selected {
+		case len(settingItems):
+			return Controls
+		case len(settingItems) + 1:
+			return scene.back()
+		}
+
+		step = 1
+	}
+
+	if step != 0 && scene.menu.selected < len(settingItems) {
+		settingItems[scene.menu.selected].change(scene.context.Config, step)
+		scene.context.Config.Apply(scene.context.Board)
+	}
+
+	return follow(scene.context.Board, Settings)
+}
+
+// back returns to the pause menu when the settings were opened from it.
+func (scene *settings) back() Scene {
+	if scene.context.Board.Status() == game.Pause {
+		return Paused
+	}
+
+	return Title
+}
+
+func (scene *settings) Draw() {
+	config := scene.context.Config
+
+	var labels []string
+	for _, item := range settingItems {
+		labels = append(labels, item.label(config))
+	}
+	labels = append(labels, fmt.Sprintf("Controls: %s", config.Profile), "Back")
+
+	rl.ClearBackground(scene.context.theme().Background)
+	rl.DrawText("Settings", 30, 30, 40, scene.context.theme().Text)
+	scene.menu.draw(scene.context, labels, 30, 90)
+
+	if scene.preview == nil || scene.preview.board.Grid() != config.Grid {
+		if scene.preview != nil {
+			scene.preview.unload()
+		}
+		scene.preview = newPreview(scene.context, config.Grid)
+	}
+
+	config.Apply(scene.preview.board)
+	scene.preview.draw(scene.context.Width-165, 90, 145)
+}
+
+// newPreview builds a demo board of grid cells to show the settings with
+// the real renderer.
+func newPreview(context *Context, grid int32) *miniature {
+	board := context.NewBoard(grid)
+	board.NewGame()
+
+	state := board.State()
+	state.Snake = nil
+	for index := int32(0); index < 4; index++ {
+		state.Snake = append(state.Snake, game.Cell{X: grid/2 - index, Y: grid / 2})
+	}
+	state.Direction = game.Right
+	state.Foods = []game.FoodCell{{Cell: game.Cell{X: grid/2 + 3, Y: grid / 2}, Kind: game.NormalApple}}
+	state.Status = game.Pause
+	if err := board.SetState(state); err != nil {
+		log.Println(err)
+	}
+
+	return newMiniature(context, board)
+}
+
+func onOff(enabled bool) string {
+	if enabled {
+		return "on"
+	}
+
+	return "off"
+}
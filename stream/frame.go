@@ -0,0 +1,74 @@
+package stream
+
+import "github.com/blackprism/goti-snake/game"
+
+// Frame carries a whole state except for the snake body, of which only the
+// new head cells and the length are sent.
+type Frame struct {
+	Keyframe bool        `json:"keyframe,omitempty"`
+	Head     []game.Cell `json:"head,omitempty"`
+	Length   int         `json:"length"`
+	State    game.State  `json:"state"`
+}
+
+func Keyframe(state game.State) Frame {
+	return newFrame(true, state.Snake, state)
+}
+
+// Diff returns the frame turning previous into next, the new head cells being
+// the only part of the body sent. A keyframe is returned when next does not
+// continue previous, typically after a new game.
+func Diff(previous game.State, next game.State) Frame {
+	for moved := 0; moved <= len(next.Snake); moved++ {
+		if continues(next.Snake[moved:], previous.Snake) {
+			return newFrame(false, next.Snake[:moved], next)
+		}
+	}
+
+	return Keyframe(next)
+}
+
+func newFrame(keyframe bool, head []game.Cell, state game.State) Frame {
+	length := len(state.Snake)
+	state.Snake = nil
+
+	return Frame{
+		Keyframe: keyframe,
+		Head:     head,
+		Length:   length,
+		State:    state,
+	}
+}
+
+func (frame Frame) Apply(state game.State) game.State {
+	var body []game.Cell
+
+	if !frame.Keyframe {
+		body = state.Snake
+	}
+
+	snake := make([]game.Cell, 0, frame.Length)
+	snake = append(snake, frame.Head...)
+	for index := 0; len(snake) < frame.Length && index < len(body); index++ {
+		snake = append(snake, body[index])
+	}
+
+	next := frame.State
+	next.Snake = snake
+
+	return next
+}
+
+func continues(tail []game.Cell, previous []game.Cell) bool {
+	if len(tail) > len(previous) || len(tail) == 0 && len(previous) > 0 {
+		return false
+	}
+
+	for index := range tail {
+		if tail[index] != previous[index] {
+			return false
+		}
+	}
+
+	return true
+}
@@ -0,0 +1,92 @@
+package stream
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/blackprism/goti-snake/game"
+)
+
+func cells(coords ...int32) []game.Cell {
+	var cells []game.Cell
+	for index := 0; index < len(coords); index += 2 {
+		cells = append(cells, game.Cell{X: coords[index], Y: coords[index+1]})
+	}
+
+	return cells
+}
+
+func state(snake []game.Cell) game.State {
+	return game.State{
+		Grid:   10,
+		Snake:  snake,
+		Status: game.Continue,
+		Foods:  []game.FoodCell{{Cell: game.Cell{X: 7, Y: 7}, Kind: game.NormalApple}},
+	}
+}
+
+func TestDiffApply(t *testing.T) {
+	moved := state(cells(3, 2, 2, 2, 1, 2))
+
+	eaten := state(cells(4, 2, 3, 2, 2, 2, 1, 2))
+	eaten.Foods = []game.FoodCell{{Cell: game.Cell{X: 1, Y: 8}, Kind: game.GoldenApple, TicksLeft: 30}}
+	eaten.Score = 3
+
+	hazard := state(cells(3, 2, 2, 2, 1, 2))
+	hazard.Hazards = []game.HazardCell{{
+		Cell:      game.Cell{X: 5, Y: 5},
+		Kind:      game.Patrol,
+		TicksLeft: 40,
+		Path:      cells(5, 5, 5, 6, 5, 7),
+	}}
+
+	tests := []struct {
+		name     string
+		previous game.State
+		next     game.State
+	}{
+		{"moves", state(cells(2, 2, 1, 2, 0, 2)), moved},
+		{"grows", moved, state(cells(4, 2, 3, 2, 2, 2, 1, 2))},
+		{"shrinks", moved, state(cells(4, 2, 3, 2))},
+		{"shrinks without moving", moved, state(cells(3, 2))},
+		{"wraps", state(cells(9, 2, 8, 2, 7, 2)), state(cells(0, 2, 9, 2, 8, 2))},
+		{"moves twice", moved, state(cells(5, 2, 4, 2, 3, 2))},
+		{"food changes", moved, eaten},
+		{"hazard spawns", moved, hazard},
+		{"new game", moved, state(cells(5, 5))},
+	}
+
+	for _, test := range tests {
+		frame := Diff(test.previous, test.next)
+
+		if got := frame.Apply(test.previous); !reflect.DeepEqual(got, test.next) {
+			t.Errorf("%s: applied %+v, want %+v", test.name, got, test.next)
+		}
+
+		if len(frame.Head) > 2 && !frame.Keyframe {
+			t.Errorf("%s: a diff sent %d head cells", test.name, len(frame.Head))
+		}
+	}
+}
+
+func TestDiffSendsOnlyTheHead(t *testing.T) {
+	previous := state(cells(2, 2, 1, 2, 0, 2))
+	frame := Diff(previous, state(cells(3, 2, 2, 2, 1, 2)))
+
+	if frame.Keyframe || !reflect.DeepEqual(frame.Head, cells(3, 2)) || frame.Length != 3 || frame.State.Snake != nil {
+		t.Fatalf("frame %+v, want the new head only", frame)
+	}
+}
+
+func TestDiffAfterANewGameIsAKeyframe(t *testing.T) {
+	frame := Diff(state(cells(2, 2, 1, 2)), state(cells(8, 8)))
+
+	if !frame.Keyframe {
+		t.Fatal("a snake which doesn't continue the previous one needs a keyframe")
+	}
+
+	// A keyframe ignores what the spectator had.
+	if got := frame.Apply(state(cells(1, 1, 1, 2, 1, 3))); !reflect.DeepEqual(got.Snake, cells(8, 8)) {
+		t.Fatalf("snake %v, want 8,8", got.Snake)
+	}
+}
@@ -0,0 +1,153 @@
+package stream
+
+import (
+	"encoding/json"
+	"io"
+	"io/ioutil"
+	"net"
+	"sync"
+
+	"github.com/blackprism/goti-snake/game"
+)
+
+const clientBuffer = 64
+
+func Listen(address string) (*Server, error) {
+	listener, err := net.Listen("tcp", address)
+	if err != nil {
+		return nil, err
+	}
+
+	server := &Server{
+		listener: listener,
+		clients:  make(map[*client]bool),
+	}
+
+	go server.serve()
+
+	return server, nil
+}
+
+type Server struct {
+	listener  net.Listener
+	mutex     sync.Mutex
+	clients   map[*client]bool
+	state     game.State
+	published bool
+}
+
+type client struct {
+	conn   net.Conn
+	frames chan Frame
+	stale  bool
+}
+
+func (server *Server) Addr() net.Addr {
+	return server.listener.Addr()
+}
+
+func (server *Server) Publish(state game.State) {
+	server.mutex.Lock()
+	defer server.mutex.Unlock()
+
+	if server.published && server.state.SameView(state) {
+		return
+	}
+
+	frame := Diff(server.state, state)
+	if !server.published {
+		frame = Keyframe(state)
+	}
+
+	server.state = state
+	server.published = true
+
+	for client := range server.clients {
+		if client.stale {
+			client.send(Keyframe(state))
+			continue
+		}
+
+		client.send(frame)
+	}
+}
+
+func (server *Server) Close() error {
+	server.mutex.Lock()
+	defer server.mutex.Unlock()
+
+	for client := range server.clients {
+		server.drop(client)
+	}
+
+	return server.listener.Close()
+}
+
+func (server *Server) serve() {
+	for {
+		conn, err := server.listener.Accept()
+		if err != nil {
+			return
+		}
+
+		client := &client{
+			conn:   conn,
+			frames: make(chan Frame, clientBuffer),
+			stale:  true,
+		}
+
+		server.mutex.Lock()
+		server.clients[client] = true
+		if server.published {
+			client.send(Keyframe(server.state))
+		}
+		server.mutex.Unlock()
+
+		go server.write(client)
+		go server.discard(client)
+	}
+}
+
+func (server *Server) write(client *client) {
+	encoder := json.NewEncoder(client.conn)
+
+	for frame := range client.frames {
+		if err := encoder.Encode(frame); err != nil {
+			break
+		}
+	}
+
+	server.mutex.Lock()
+	server.drop(client)
+	server.mutex.Unlock()
+}
+
+// Spectators are read-only, anything they send is ignored.
+func (server *Server) discard(client *client) {
+	_, _ = io.Copy(ioutil.Discard, client.conn)
+
+	server.mutex.Lock()
+	server.drop(client)
+	server.mutex.Unlock()
+}
+
+func (server *Server) drop(client *client) {
+	if !server.clients[client] {
+		return
+	}
+
+	delete(server.clients, client)
+	close(client.frames)
+	_ = client.conn.Close()
+}
+
+// A spectator too slow to keep up misses frames, it gets a keyframe as soon
+// as there is room again so gameplay never waits on the network.
+func (client *client) send(frame Frame) {
+	select {
+	case client.frames <- frame:
+		client.stale = false
+	default:
+		client.stale = true
+	}
+}
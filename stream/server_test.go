@@ -0,0 +1,147 @@
+package stream
+
+import (
+	"encoding/json"
+	"net"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/blackprism/goti-snake/game"
+)
+
+func listen(t *testing.T) *Server {
+	server, err := Listen("127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { _ = server.Close() })
+
+	return server
+}
+
+// watch connects a raw spectator to read the frames as sent.
+func watch(t *testing.T, server *Server) *json.Decoder {
+	conn, err := net.Dial("tcp", server.Addr().String())
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { _ = conn.Close() })
+	_ = conn.SetReadDeadline(time.Now().Add(5 * time.Second))
+
+	return json.NewDecoder(conn)
+}
+
+func next(t *testing.T, decoder *json.Decoder) Frame {
+	t.Helper()
+
+	var frame Frame
+	if err := decoder.Decode(&frame); err != nil {
+		t.Fatal(err)
+	}
+
+	return frame
+}
+
+func TestLateSpectatorGetsAKeyframe(t *testing.T) {
+	server := listen(t)
+
+	server.Publish(state(cells(1, 2, 0, 2)))
+	server.Publish(state(cells(2, 2, 1, 2)))
+	latest := state(cells(3, 2, 2, 2))
+	server.Publish(latest)
+
+	decoder := watch(t, server)
+
+	frame := next(t, decoder)
+	if !frame.Keyframe {
+		t.Fatal("a late spectator should get a keyframe first")
+	}
+
+	seen := frame.Apply(game.State{})
+	if !reflect.DeepEqual(seen, latest) {
+		t.Fatalf("keyframe %+v, want %+v", seen, latest)
+	}
+
+	moved := state(cells(4, 2, 3, 2))
+	server.Publish(moved)
+
+	frame = next(t, decoder)
+	if frame.Keyframe || len(frame.Head) != 1 {
+		t.Fatalf("frame %+v, want a diff with the new head", frame)
+	}
+
+	if seen = frame.Apply(seen); !reflect.DeepEqual(seen, moved) {
+		t.Fatalf("applied %+v, want %+v", seen, moved)
+	}
+}
+
+func TestSpectatorFollowsTheGame(t *testing.T) {
+	server := listen(t)
+	server.Publish(state(cells(1, 2, 0, 2)))
+
+	spectator, err := Dial(server.Addr().String())
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { _ = spectator.Close() })
+
+	if _, err := spectator.Next(); err != nil {
+		t.Fatal(err)
+	}
+
+	moved := state(cells(2, 2, 1, 2))
+	server.Publish(moved)
+
+	seen, err := spectator.Next()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !reflect.DeepEqual(seen, moved) {
+		t.Fatalf("spectator sees %+v, want %+v", seen, moved)
+	}
+}
+
+func TestPublishSkipsWhatDoesntShow(t *testing.T) {
+	late := &client{frames: make(chan Frame, clientBuffer)}
+	server := &Server{clients: map[*client]bool{late: true}}
+
+	first := state(cells(1, 2, 0, 2))
+	server.Publish(first)
+
+	ticked := first
+	ticked.Frames = 12
+	ticked.MoveFrames = 3
+	server.Publish(ticked)
+
+	if len(late.frames) != 1 {
+		t.Fatalf("%d frames sent, the frame counters don't change the picture", len(late.frames))
+	}
+}
+
+func TestBehindClientGetsAKeyframe(t *testing.T) {
+	behind := &client{frames: make(chan Frame, 1)}
+	server := &Server{clients: map[*client]bool{behind: true}}
+
+	server.Publish(state(cells(1, 2, 0, 2)))
+	server.Publish(state(cells(2, 2, 1, 2)))
+
+	if !behind.stale {
+		t.Fatal("a client with a full buffer should be behind")
+	}
+
+	<-behind.frames
+	latest := state(cells(3, 2, 2, 2))
+	server.Publish(latest)
+
+	frame := <-behind.frames
+	if !frame.Keyframe || !reflect.DeepEqual(frame.Apply(game.State{}), latest) {
+		t.Fatalf("frame %+v, want a keyframe of the latest state", frame)
+	}
+
+	server.Publish(state(cells(4, 2, 3, 2)))
+	if frame := <-behind.frames; frame.Keyframe {
+		t.Fatal("a client caught up should get diffs again")
+	}
+}
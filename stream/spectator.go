@@ -0,0 +1,49 @@
+package stream
+
+import (
+	"encoding/json"
+	"net"
+
+	"github.com/blackprism/goti-snake/game"
+)
+
+func Dial(address string) (*Spectator, error) {
+	conn, err := net.Dial("tcp", address)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Spectator{
+		conn:    conn,
+		decoder: json.NewDecoder(conn),
+	}, nil
+}
+
+type Spectator struct {
+	conn     net.Conn
+	decoder  *json.Decoder
+	state    game.State
+	keyframe bool
+}
+
+func (spectator *Spectator) Next() (game.State, error) {
+	for {
+		var frame Frame
+		if err := spectator.decoder.Decode(&frame); err != nil {
+			return game.State{}, err
+		}
+
+		if !frame.Keyframe && !spectator.keyframe {
+			continue
+		}
+
+		spectator.keyframe = true
+		spectator.state = frame.Apply(spectator.state)
+
+		return spectator.state, nil
+	}
+}
+
+func (spectator *Spectator) Close() error {
+	return spectator.conn.Close()
+}
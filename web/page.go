@@ -0,0 +1,203 @@
+package web
+
+const page = `<!DOCTYPE html>
+<html>
+<head>
+<meta charset="utf-8">
+<title>Goti Snake</title>
+<style>
+	body { margin: 0; background: #f0f0f0; font-family: sans-serif; }
+	#game { display: block; margin: 20px auto; }
+</style>
+</head>
+<body>
+<canvas id="game" width="600" height="640"></canvas>
+<script>
+	const canvas = document.getElementById("game");
+	const context = canvas.getContext("2d");
+	const size = 600, border = 20, menu = 40;
+	const messages = {
+		newgame: "Press [ENTER] for a New game",
+		pause: "Pause",
+		gameover: "You lose !",
+		victory: "Victory !"
+	};
+	const deaths = {
+		wall: "You hit the wall",
+		itself: "You bit your own body",
+		"turned-back": "You turned back on yourself",
+		bomb: "You ate a bomb",
+		poison: "The poison was too much for you",
+		patrol: "You ran into a patrol"
+	};
+	const powerUpColors = {
+		ghost: "rgb(150, 150, 220)",
+		slow: "rgb(90, 190, 190)",
+		magnet: "rgb(200, 60, 140)",
+		shield: "rgb(0, 121, 241)"
+	};
+	const keys = {
+		ArrowUp: {action: "turn", direction: "up"},
+		ArrowDown: {action: "turn", direction: "down"},
+		ArrowLeft: {action: "turn", direction: "left"},
+		ArrowRight: {action: "turn", direction: "right"},
+		" ": {action: "pause"},
+		Enter: {action: "new-game"},
+		n: {action: "new-game"}
+	};
+
+	const socket = new WebSocket((location.protocol === "https:" ? "wss://" : "ws://") + location.host + "/ws");
+	socket.onmessage = event => draw(JSON.parse(event.data));
+	socket.onclose = () => drawText("Connection lost", 20);
+
+	document.addEventListener("keydown", event => {
+		const message = keys[event.key];
+		if (message && socket.readyState === WebSocket.OPEN) {
+			socket.send(JSON.stringify(message));
+			event.preventDefault();
+		}
+	});
+
+	function draw(state) {
+		const cell = Math.floor((size - 2 * border) / state.grid);
+
+		context.fillStyle = "#fff";
+		context.fillRect(0, 0, size, menu);
+		context.fillStyle = "rgb(66, 66, 66)";
+		context.fillRect(0, menu, size, size);
+		context.fillStyle = "#fff";
+		context.fillRect(border, menu + border, size - 2 * border, size - 2 * border);
+
+		state.foods.forEach(food => drawFood(food, cell));
+		state.hazards.forEach(hazard => drawHazard(hazard, cell));
+		state.powerUps.forEach(powerUp => drawPowerUp(powerUp, cell));
+
+		const step = 140 / state.snake.length;
+		const effects = {};
+		state.effects.forEach(effect => effects[effect.kind] = effect);
+
+		context.globalAlpha = effects.ghost ? 110 / 255 : 1;
+		state.snake.forEach((position, index) => {
+			const shade = Math.min(140, step * index);
+			context.fillStyle = "rgb(" + (47 + shade) + "," + (78 + shade) + "," + shade + ")";
+			context.fillRect(border + position.x * cell, menu + border + position.y * cell, cell, cell);
+		});
+		context.globalAlpha = 1;
+
+		if (effects.shield) {
+			const head = state.snake[0];
+			context.strokeStyle = powerUpColors.shield;
+			context.lineWidth = 2;
+			context.strokeRect(border + head.x * cell - 1, menu + border + head.y * cell - 1, cell + 2, cell + 2);
+			context.lineWidth = 1;
+		}
+
+		context.fillStyle = "#000";
+		context.font = "20px sans-serif";
+		context.fillText(state.mode + "  " + state.hud, 10, 28);
+		drawEffects(state);
+		context.font = "16px sans-serif";
+		context.textAlign = "right";
+		context.fillText(state.difficulty + " " + state.cellsPerSecond.toFixed(1) + " cells/s", size - 10, 26);
+		context.textAlign = "left";
+		if (messages[state.status]) {
+			drawText(messages[state.status], 120);
+		}
+		if (state.status !== "continue" && state.status !== "pause") {
+			drawText("Mode [UP/DOWN]: " + state.mode, 220);
+			drawText("Difficulty [LEFT/RIGHT]: " + state.difficulty, 260);
+		}
+		if (state.status === "gameover" && deaths[state.death]) {
+			drawText(deaths[state.death], 170);
+		}
+	}
+
+	function drawFood(food, cell) {
+		const x = border + food.x * cell, y = menu + border + food.y * cell;
+
+		if (food.ticksLeft > 0 && food.ticksLeft <= 10 && food.ticksLeft % 2 === 0) {
+			return;
+		}
+
+		switch (food.kind) {
+		case "golden":
+			context.fillStyle = "rgb(230, 180, 30)";
+			context.fillRect(x, y, cell, cell);
+			context.fillStyle = "rgb(255, 240, 170)";
+			context.fillRect(x + cell / 3, y + cell / 3, cell / 3, cell / 3);
+			break;
+		case "shrinking":
+			context.fillStyle = "rgb(128, 60, 160)";
+			context.fillRect(x + cell / 4, y + cell / 4, cell / 2, cell / 2);
+			break;
+		case "speed":
+			drawCircle(x, y, cell, cell / 2, "rgb(50, 120, 220)");
+			break;
+		default:
+			context.fillStyle = "rgb(192, 70, 67)";
+			context.fillRect(x, y, cell, cell);
+		}
+	}
+
+	function drawHazard(hazard, cell) {
+		const x = border + hazard.x * cell, y = menu + border + hazard.y * cell;
+
+		switch (hazard.kind) {
+		case "poison":
+			drawCircle(x, y, cell, cell / 2, "rgb(90, 140, 40)");
+			drawCircle(x, y, cell, cell / 4, "rgb(30, 50, 10)");
+			break;
+		case "bomb":
+			drawCircle(x, y, cell, cell / 2, "#000");
+			drawCircle(x, y, cell, cell / 6, "rgb(230, 41, 55)");
+			break;
+		case "patrol":
+			context.fillStyle = "rgb(255, 161, 0)";
+			context.fillRect(x, y, cell, cell);
+			context.strokeStyle = "rgb(190, 33, 55)";
+			context.strokeRect(x, y, cell, cell);
+			break;
+		}
+	}
+
+	function drawPowerUp(powerUp, cell) {
+		const x = border + powerUp.x * cell, y = menu + border + powerUp.y * cell;
+
+		context.strokeStyle = powerUpColors[powerUp.kind];
+		context.strokeRect(x, y, cell, cell);
+		context.fillStyle = powerUpColors[powerUp.kind];
+		context.fillRect(x + cell / 4, y + cell / 4, cell / 2, cell / 2);
+	}
+
+	function drawEffects(state) {
+		let x = 20 + context.measureText(state.mode + "  " + state.hud).width;
+
+		context.font = "16px sans-serif";
+		state.effects.forEach(effect => {
+			const label = effect.kind + (effect.kind === "shield" ? " x" + effect.charges : " " + effect.ticksLeft);
+			context.fillStyle = powerUpColors[effect.kind];
+			context.fillRect(x, 12, 16, 16);
+			context.fillStyle = "#555";
+			context.fillText(label, x + 20, 26);
+			x += 30 + context.measureText(label).width;
+		});
+	}
+
+	function drawCircle(x, y, cell, radius, color) {
+		context.fillStyle = color;
+		context.beginPath();
+		context.arc(x + cell / 2, y + cell / 2, radius, 0, 2 * Math.PI);
+		context.fill();
+	}
+
+	function drawText(text, y) {
+		context.fillStyle = "#000";
+		context.font = "30px sans-serif";
+		context.textAlign = "center";
+		context.fillText(text, size / 2, menu + y);
+		context.textAlign = "left";
+	}
+</script>
+</body>
+</html>
+`
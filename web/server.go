@@ -0,0 +1,153 @@
+package web
+
+import (
+	"encoding/json"
+	"log"
+	"net/http"
+	"time"
+
+	"github.com/blackprism/goti-snake/game"
+	"github.com/gorilla/websocket"
+)
+
+func NewServer(newBoard func() *game.Board) *Server {
+	server := &Server{
+		newBoard: newBoard,
+		mux:      http.NewServeMux(),
+	}
+
+	server.mux.HandleFunc("/", server.handlePage)
+	server.mux.HandleFunc("/ws", server.handleSocket)
+
+	return server
+}
+
+// Server lets a browser play: the page only draws the states it receives
+// and sends back the keys pressed, each connection gets its own board
+// ticking on the server.
+type Server struct {
+	newBoard func() *game.Board
+	mux      *http.ServeMux
+	upgrader websocket.Upgrader
+}
+
+// message is what the page sends, Direction is only set for a turn.
+type message struct {
+	Action    string          `json:"action"`
+	Direction *game.Direction `json:"direction,omitempty"`
+}
+
+func (server *Server) ServeHTTP(writer http.ResponseWriter, request *http.Request) {
+	server.mux.ServeHTTP(writer, request)
+}
+
+func (server *Server) handlePage(writer http.ResponseWriter, request *http.Request) {
+	if request.URL.Path != "/" {
+		http.NotFound(writer, request)
+		return
+	}
+
+	writer.Header().Set("Content-Type", "text/html; charset=utf-8")
+	_, _ = writer.Write([]byte(page))
+}
+
+func (server *Server) handleSocket(writer http.ResponseWriter, request *http.Request) {
+	conn, err := server.upgrader.Upgrade(writer, request, nil)
+	if err != nil {
+		return
+	}
+	defer conn.Close()
+
+	server.play(conn)
+}
+
+func (server *Server) play(conn *websocket.Conn) {
+	board := server.newBoard()
+	board.SpawnFood()
+	messages := make(chan message)
+	done := make(chan struct{})
+	defer close(done)
+	go read(conn, messages, done)
+
+	ticker := time.NewTicker(time.Second / game.FramesPerSecond)
+	defer ticker.Stop()
+
+	var sent game.State
+	for first := true; ; first = false {
+		select {
+		case message, ok := <-messages:
+			if !ok {
+				return
+			}
+			apply(board, message)
+		case <-ticker.C:
+			board.Tick()
+		}
+
+		// The frame counters change every tick, a state is only sent when
+		// the picture changes.
+		state := board.State()
+		if !first && sent.SameView(state) {
+			continue
+		}
+
+		if err := conn.WriteJSON(state); err != nil {
+			log.Println(err)
+			return
+		}
+		sent = state
+	}
+}
+
+func read(conn *websocket.Conn, messages chan<- message, done <-chan struct{}) {
+	defer close(messages)
+
+	for {
+		_, data, err := conn.ReadMessage()
+		if err != nil {
+			return
+		}
+
+		var message message
+		if err := json.Unmarshal(data, &message); err != nil {
+			continue
+		}
+
+		if message.Action == "turn" && message.Direction == nil {
+			continue
+		}
+
+		select {
+		case messages <- message:
+		case <-done:
+			return
+		}
+	}
+}
+
+func apply(board *game.Board, message message) {
+	switch message.Action {
+	case "new-game":
+		board.Start()
+	case "pause":
+		if board.Running() {
+			board.SetPaused(board.Status() != game.Pause)
+		}
+	case "turn":
+		if board.Running() {
+			board.Turn(*message.Direction)
+			return
+		}
+
+		switch *message.Direction {
+		case game.Left:
+			board.SetDifficulty(board.Difficulty().Previous())
+		case game.Right:
+			board.SetDifficulty(board.Difficulty().Next())
+		case game.Up:
+			board.SetMode(board.Mode().Previous())
+		case game.Down:
+			board.SetMode(board.Mode().Next())
+		}
+	}
+}
@@ -0,0 +1,135 @@
+package web
+
+import (
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/blackprism/goti-snake/game"
+	"github.com/gorilla/websocket"
+)
+
+func newBoard() *game.Board {
+	position := game.NewCoordinateConverter(20, 60, 28)
+	snake := game.NewSnake(20, position)
+	snake.Init()
+
+	return game.NewBoard(snake, 600, 20, 20, 0.2, position)
+}
+
+func dial(t *testing.T) *websocket.Conn {
+	server := httptest.NewServer(NewServer(newBoard))
+	t.Cleanup(server.Close)
+
+	conn, _, err := websocket.DefaultDialer.Dial("ws"+strings.TrimPrefix(server.URL, "http")+"/ws", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { conn.Close() })
+
+	if err := conn.SetReadDeadline(time.Now().Add(5 * time.Second)); err != nil {
+		t.Fatal(err)
+	}
+
+	return conn
+}
+
+// next reads states until one matches, failing at the read deadline.
+func next(t *testing.T, conn *websocket.Conn, match func(game.State) bool) game.State {
+	for {
+		var state game.State
+		if err := conn.ReadJSON(&state); err != nil {
+			t.Fatal(err)
+		}
+
+		if match(state) {
+			return state
+		}
+	}
+}
+
+func TestKeyframeOnConnect(t *testing.T) {
+	conn := dial(t)
+
+	var state game.State
+	if err := conn.ReadJSON(&state); err != nil {
+		t.Fatal(err)
+	}
+
+	if state.Grid != 20 || len(state.Snake) == 0 || len(state.Foods) == 0 {
+		t.Fatalf("first state is not a full keyframe: %+v", state)
+	}
+
+	if state.Status != game.NewGame {
+		t.Fatalf("status = %s, want %s", state.Status, game.NewGame)
+	}
+}
+
+func TestInputTurnsTheBoard(t *testing.T) {
+	conn := dial(t)
+
+	if err := conn.WriteJSON(message{Action: "new-game"}); err != nil {
+		t.Fatal(err)
+	}
+
+	state := next(t, conn, func(state game.State) bool { return state.Status == game.Continue })
+
+	turn := game.Left
+	if state.Direction == game.Left || state.Direction == game.Right {
+		turn = game.Up
+	}
+
+	if err := conn.WriteJSON(message{Action: "turn", Direction: &turn}); err != nil {
+		t.Fatal(err)
+	}
+
+	next(t, conn, func(state game.State) bool { return state.Direction == turn })
+}
+
+func TestTurnWithoutDirectionIsIgnored(t *testing.T) {
+	conn := dial(t)
+	first := next(t, conn, func(game.State) bool { return true })
+
+	// Without a game running, left lowers the difficulty and down picks
+	// the next mode.
+	if err := conn.WriteMessage(websocket.TextMessage, []byte(`{"action":"turn"}`)); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := conn.WriteMessage(websocket.TextMessage, []byte(`{"action":"turn","direction":"down"}`)); err != nil {
+		t.Fatal(err)
+	}
+
+	state := next(t, conn, func(state game.State) bool { return state.Mode != first.Mode })
+	if state.Difficulty != first.Difficulty {
+		t.Fatalf("difficulty = %s, a turn without direction changed it", state.Difficulty)
+	}
+}
+
+func TestStatesFollowTheMoves(t *testing.T) {
+	conn := dial(t)
+
+	if err := conn.WriteJSON(message{Action: "new-game"}); err != nil {
+		t.Fatal(err)
+	}
+	next(t, conn, func(state game.State) bool { return state.Status == game.Continue })
+
+	if err := conn.SetReadDeadline(time.Now().Add(500 * time.Millisecond)); err != nil {
+		t.Fatal(err)
+	}
+
+	received := 0
+	for {
+		var state game.State
+		if err := conn.ReadJSON(&state); err != nil {
+			break
+		}
+		received++
+	}
+
+	// The board ticks 120 times in half a second, the snake moves a few.
+	if received == 0 || received > game.FramesPerSecond/8 {
+		t.Fatalf("%d states in half a second, want one per move", received)
+	}
+}